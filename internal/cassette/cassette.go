@@ -0,0 +1,337 @@
+// Package cassette records HTTP interactions of the DevOps API client to
+// files and replays them deterministically, so behaviour observed against a
+// real API can be captured once and kept as a regression test.
+//
+// The mode is normally chosen with the DEVOPS_CASSETTE_MODE environment
+// variable: "record" talks to the API and saves the cassette, "replay"
+// serves responses from the cassette without touching the network, and
+// "off" bypasses cassettes entirely. When unset, existing cassettes are
+// replayed and tests without one talk to the API directly.
+//
+// Replay serves recorded interactions in order. Reads may be repeated more
+// often than they were recorded, since Terraform decides how many times it
+// refreshes, but every write has to match a recorded one.
+package cassette
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ModeEnvVar selects the cassette mode.
+const ModeEnvVar = "DEVOPS_CASSETTE_MODE"
+
+// Mode controls whether a Recorder records, replays or passes through.
+type Mode int
+
+const (
+	// ModeAuto replays the cassette if it exists and passes through
+	// otherwise.
+	ModeAuto Mode = iota
+	// ModeRecord sends requests to the API and saves the interactions.
+	ModeRecord
+	// ModeReplay answers requests from the cassette only.
+	ModeReplay
+	// ModeOff sends requests to the API without recording.
+	ModeOff
+)
+
+// ModeFromEnv returns the mode selected by DEVOPS_CASSETTE_MODE.
+func ModeFromEnv() (Mode, error) {
+	switch value := strings.ToLower(os.Getenv(ModeEnvVar)); value {
+	case "", "auto":
+		return ModeAuto, nil
+	case "record":
+		return ModeRecord, nil
+	case "replay":
+		return ModeReplay, nil
+	case "off":
+		return ModeOff, nil
+	default:
+		return ModeAuto, fmt.Errorf("invalid %s %q: expected record, replay, off or auto", ModeEnvVar, value)
+	}
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded part of an HTTP request. The URL omits scheme
+// and host so cassettes replay against any endpoint.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is the recorded part of an HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper backed by a cassette file.
+type Recorder struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New returns a Recorder for the cassette at path. ModeAuto is resolved
+// based on whether the file exists. next performs live requests and
+// defaults to http.DefaultTransport.
+func New(path string, mode Mode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if mode == ModeAuto {
+		mode = ModeOff
+		if _, err := os.Stat(path); err == nil {
+			mode = ModeReplay
+		}
+	}
+
+	r := &Recorder{path: path, mode: mode, next: next}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the resolved mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, req, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := Request{
+		Method:  req.Method,
+		URL:     req.URL.RequestURI(),
+		Headers: redactHeaders(req.Header),
+		Body:    redactBody(body),
+	}
+
+	switch r.mode {
+	case ModeReplay:
+		return r.replay(req, recorded)
+	case ModeRecord:
+		return r.record(req, recorded)
+	default:
+		return r.next.RoundTrip(req)
+	}
+}
+
+// Stop saves the cassette when recording. It is a no-op in other modes.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Cassettes hold readable text, so compressed bodies are stored
+	// decompressed and replayed without the encoding.
+	headers := redactHeaders(res.Header)
+	if strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("decompressing response for cassette: %w", err)
+		}
+		body, err = io.ReadAll(gz)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing response for cassette: %w", err)
+		}
+		headers.Del("Content-Encoding")
+		headers.Del("Content-Length")
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Headers:    headers,
+			Body:       redactBody(string(body)),
+		},
+	})
+	r.mu.Unlock()
+
+	return res, nil
+}
+
+// replay answers req with the first unused interaction with the same
+// method, URL and body, so repeated identical requests replay in order.
+// Once every matching read has been used, the last one answers again:
+// Terraform refreshes the same objects a varying number of times, which a
+// cassette cannot know in advance. Writes are never replayed twice.
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, interaction := range r.interactions {
+		if interaction.Request.Method != recorded.Method ||
+			interaction.Request.URL != recorded.URL ||
+			interaction.Request.Body != recorded.Body {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return r.response(req, interaction), nil
+		}
+		last = i
+	}
+
+	if last >= 0 && (recorded.Method == http.MethodGet || recorded.Method == http.MethodHead) {
+		return r.response(req, r.interactions[last]), nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no unused interaction for %s %s", r.path, recorded.Method, recorded.URL)
+}
+
+// response builds the replayed response of interaction to req.
+func (r *Recorder) response(req *http.Request, interaction Interaction) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Headers.Clone(),
+		Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}
+}
+
+// readRequestBody returns the body of req together with the request to
+// pass on. A RoundTripper must not modify the caller's request, so the body
+// is read from a copy when the request can make one, and otherwise handed
+// on in a clone of the request.
+func readRequestBody(req *http.Request) (string, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", req, nil
+	}
+
+	if req.GetBody != nil {
+		copied, err := req.GetBody()
+		if err != nil {
+			return "", nil, err
+		}
+		body, err := io.ReadAll(copied)
+		copied.Close()
+		if err != nil {
+			return "", nil, err
+		}
+		return string(body), req, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+
+	return string(body), clone, nil
+}
+
+// sensitiveHeaders are replaced in recorded interactions.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// volatileHeaders change on every request and would only add noise to
+// cassette diffs.
+var volatileHeaders = []string{"Date", "User-Agent", "Idempotency-Key", "Traceparent", "Tracestate"}
+
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, key := range sensitiveHeaders {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, "REDACTED")
+		}
+	}
+	for _, key := range volatileHeaders {
+		redacted.Del(key)
+	}
+	if len(redacted) == 0 {
+		return nil
+	}
+	return redacted
+}
+
+// emailPattern matches email addresses in recorded bodies.
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@([A-Za-z0-9\-]+\.)+[A-Za-z]{2,}`)
+
+// redactBody masks email addresses. Addresses on the RFC 2606 example
+// domains are kept, since tests use them in configuration and expect them
+// back from the API.
+func redactBody(body string) string {
+	return emailPattern.ReplaceAllStringFunc(body, func(email string) string {
+		domain := strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+		for _, reserved := range []string{"example.com", "example.org", "example.net"} {
+			if domain == reserved || strings.HasSuffix(domain, "."+reserved) {
+				return email
+			}
+		}
+		return "redacted@example.com"
+	})
+}
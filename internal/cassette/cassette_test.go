@@ -0,0 +1,228 @@
+package cassette
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"1","name":"Ada","email":"ada@corp.io"},{"id":"2","name":"Grace","email":"grace@example.com"}]`))
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassettes", "engineers.json")
+
+	recorder, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.NewClient(server.URL, client.WithToken("s3cret"), client.WithTransport(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, err := c.GetEngineers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	if live[0].Email != "ada@corp.io" {
+		t.Errorf("recording altered the live response: got %q", live[0].Email)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"s3cret", "ada@corp.io"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), "grace@example.com") {
+		t.Errorf("cassette lost example.com address:\n%s", data)
+	}
+
+	// The server is gone, so the replay can only be served from the file,
+	// and any host works since only the request URI is matched.
+	recorder, err = New(path, ModeAuto, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorder.Mode() != ModeReplay {
+		t.Fatalf("expected auto mode to replay an existing cassette, got %v", recorder.Mode())
+	}
+	c, err = client.NewClient("http://devops.invalid", client.WithToken("s3cret"), client.WithTransport(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := c.GetEngineers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(replayed) != 2 || replayed[0].Email != "redacted@example.com" || replayed[1].Email != "grace@example.com" {
+		t.Errorf("unexpected replayed engineers: %+v", replayed)
+	}
+}
+
+func TestRecordGzipResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, _ = gz.Write([]byte(`[{"id":"1","name":"Ada","email":"ada@example.com"}]`))
+		_ = gz.Close()
+
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "gzip.json")
+	recorder, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := client.NewClient(server.URL, client.WithTransport(recorder))
+	if _, err := c.GetEngineers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `ada@example.com`) || strings.Contains(string(data), "Content-Encoding") {
+		t.Errorf("expected a decompressed body in the cassette:\n%s", data)
+	}
+}
+
+func TestReplayUnmatchedRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://devops.invalid/engineers", nil)
+	if _, err := recorder.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "no unused interaction") {
+		t.Errorf("expected unmatched request error, got %v", err)
+	}
+}
+
+func TestReplayRepeatsLastRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reads.json")
+	cassette := `[
+  {"request": {"method": "GET", "url": "/engineers"}, "response": {"status_code": 200, "body": "[]"}},
+  {"request": {"method": "GET", "url": "/engineers"}, "response": {"status_code": 200, "body": "[{\"id\":\"1\"}]"}},
+  {"request": {"method": "POST", "url": "/engineers", "body": "{}"}, "response": {"status_code": 201, "body": "{\"id\":\"1\"}"}}
+]`
+	if err := os.WriteFile(path, []byte(cassette), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads replay in order and then keep answering with the last one.
+	for _, expected := range []string{`[]`, `[{"id":"1"}]`, `[{"id":"1"}]`} {
+		req := httptest.NewRequest(http.MethodGet, "http://devops.invalid/engineers", nil)
+		res, err := recorder.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		if string(body) != expected {
+			t.Errorf("expected %s, got %s", expected, body)
+		}
+	}
+
+	// A write is only replayed as often as it was recorded.
+	for i := range 2 {
+		req := httptest.NewRequest(http.MethodPost, "http://devops.invalid/engineers", strings.NewReader(`{}`))
+		_, err := recorder.RoundTrip(req)
+		if i == 0 && err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && err == nil {
+			t.Error("expected a second create to find no interaction")
+		}
+	}
+}
+
+func TestRecordLeavesRequestUnchanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada@example.com"}`))
+	}))
+	defer server.Close()
+
+	recorder, err := New(filepath.Join(t.TempDir(), "create.json"), ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A body without GetBody cannot be copied, so the recorder has to read
+	// it and must then pass on a clone rather than swap the body.
+	body := io.NopCloser(strings.NewReader(`{"name":"Ada","email":"ada@example.com"}`))
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/engineers", body)
+	res, err := recorder.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if req.Body != body {
+		t.Error("expected the caller's request body to be left alone")
+	}
+	if got := recorder.interactions[0].Request.Body; !strings.Contains(got, `"name":"Ada"`) {
+		t.Errorf("expected the request body to be recorded, got %q", got)
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	for value, want := range map[string]Mode{
+		"":       ModeAuto,
+		"record": ModeRecord,
+		"REPLAY": ModeReplay,
+		"off":    ModeOff,
+	} {
+		t.Setenv(ModeEnvVar, value)
+		got, err := ModeFromEnv()
+		if err != nil || got != want {
+			t.Errorf("%q: got %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	t.Setenv(ModeEnvVar, "rewind")
+	if _, err := ModeFromEnv(); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
@@ -0,0 +1,29 @@
+package client
+
+import "net/http"
+
+// WithToken authenticates every request with the given bearer token.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithBasicAuth authenticates every request with HTTP basic credentials.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// setAuth attaches the configured credentials to req. A bearer token takes
+// precedence over basic credentials.
+func (c *Client) setAuth(req *http.Request) {
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+}
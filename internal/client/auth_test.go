@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorizationHeader(t *testing.T) {
+	testCases := map[string]struct {
+		opts     []Option
+		expected string
+	}{
+		"none":  {expected: ""},
+		"token": {opts: []Option{WithToken("s3cr3t")}, expected: "Bearer s3cr3t"},
+		"basic": {opts: []Option{WithBasicAuth("ada", "pw")}, expected: "Basic YWRhOnB3"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+				_, _ = w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			c, _ := NewClient(server.URL, testCase.opts...)
+			if _, err := c.GetDevelopers(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != testCase.expected {
+				t.Errorf("expected Authorization %q, got %q", testCase.expected, got)
+			}
+		})
+	}
+}
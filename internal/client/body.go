@@ -0,0 +1,98 @@
+package client
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxResponseBytes caps the size of a decoded response body unless
+// WithMaxResponseBytes says otherwise.
+const DefaultMaxResponseBytes = 32 << 20
+
+// ErrResponseTooLarge is returned when a response body exceeds the
+// configured maximum size.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// WithMaxResponseBytes caps the size of every response body after
+// decompression, so a misbehaving API cannot exhaust memory. Zero or less
+// removes the cap.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// decodeFunc consumes a successful response body as it streams in.
+type decodeFunc func(res *http.Response, body io.Reader) error
+
+// responseBody returns the body of res decompressed and capped at the
+// client's maximum response size.
+func (c *Client) responseBody(req *http.Request, res *http.Response) (io.Reader, error) {
+	var body io.Reader = res.Body
+
+	// Asking for gzip ourselves turns off the transport's transparent
+	// decompression, so the encoding has to be handled here.
+	if strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading gzip response: %w", err)
+		}
+		body = gz
+		res.Header.Del("Content-Encoding")
+		res.Header.Del("Content-Length")
+		res.ContentLength = -1
+		res.Uncompressed = true
+	}
+
+	if c.maxResponseBytes > 0 {
+		body = &limitedReader{r: body, remaining: c.maxResponseBytes, req: req, limit: c.maxResponseBytes}
+	}
+
+	return body, nil
+}
+
+// limitedReader fails with ErrResponseTooLarge once more than limit bytes
+// are read, rather than silently truncating like io.LimitReader.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	req       *http.Request
+	limit     int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, l.err()
+	}
+
+	// Read one byte past the limit to tell a body of exactly limit bytes
+	// from a longer one.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), l.err()
+	}
+	return n, err
+}
+
+func (l *limitedReader) err() error {
+	return fmt.Errorf("%w: %s %s returned more than %d bytes", ErrResponseTooLarge, l.req.Method, l.req.URL.Redacted(), l.limit)
+}
+
+// invalidBody reports whether err from decoding a complete response means
+// the body itself is unusable. Sending the request again would only
+// produce the same body, unlike a body that broke off in transit.
+func invalidBody(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.Is(err, ErrResponseTooLarge) || errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGzipResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+
+		body := `[{"id":"1","name":"Ada","email":"ada@example.com"}]`
+		if r.URL.Path == "/engineers/1" {
+			body = `{"id":"1","name":"Ada","email":"ada@example.com"}`
+		}
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, _ = gz.Write([]byte(body))
+		_ = gz.Close()
+
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+
+	engineers, err := c.engineers.fetchAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(engineers) != 1 || engineers[0].Name != "Ada" {
+		t.Errorf("unexpected engineers: %+v", engineers)
+	}
+
+	engineer, err := c.engineers.fetchOne(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if engineer.Email != "ada@example.com" {
+		t.Errorf("unexpected engineer: %+v", engineer)
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	body := `[{"id":"1","name":"Ada","email":"ada@example.com"}]`
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path == "/engineers/1" {
+			_, _ = w.Write([]byte(`{"id":"1","name":"` + strings.Repeat("a", len(body)) + `"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithMaxResponseBytes(int64(len(body))))
+	if _, err := c.engineers.fetchAll(context.Background()); err != nil {
+		t.Fatalf("expected a body of exactly the limit to be accepted, got %s", err)
+	}
+
+	c, _ = NewClient(server.URL, WithMaxResponseBytes(int64(len(body)-1)))
+	hits.Store(0)
+	_, err := c.engineers.fetchAll(context.Background())
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge for a streamed collection, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "GET") || !strings.Contains(err.Error(), "/engineers") {
+		t.Errorf("expected the request in the error, got %q", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("expected oversized responses not to be retried, got %d requests", hits.Load())
+	}
+
+	if _, err := c.engineers.fetchOne(context.Background(), "1"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge for a buffered response, got %v", err)
+	}
+}
+
+func TestDecodePage(t *testing.T) {
+	testCases := map[string]struct {
+		body     string
+		expected int
+		cursor   string
+		wantErr  bool
+	}{
+		"array":          {body: " \n[{\"id\":\"1\"},{\"id\":\"2\"}]", expected: 2},
+		"empty-array":    {body: `[]`},
+		"data-envelope":  {body: `{"data":[{"id":"1"}],"next_cursor":"abc"}`, expected: 1, cursor: "abc"},
+		"items-envelope": {body: `{"items":[{"id":"1"}]}`, expected: 1},
+		"truncated":      {body: `[{"id":"1"},`, wantErr: true},
+		"html":           {body: `<html></html>`, wantErr: true},
+		"string":         {body: `"engineers"`, wantErr: true},
+		"empty":          {body: ``, wantErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			items, cursor, err := decodePage[Engineer](strings.NewReader(testCase.body))
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", items)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(items) != testCase.expected || cursor != testCase.cursor {
+				t.Errorf("expected %d items and cursor %q, got %+v and %q", testCase.expected, testCase.cursor, items, cursor)
+			}
+		})
+	}
+}
+
+func TestTruncatedStreamedBodyIsRetried(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `[{"id":"1","name":"Ada","email":"ada@example.com"}]`
+		if hits.Add(1) == 1 {
+			// Promise the whole body but break off halfway through.
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			_, _ = w.Write([]byte(body[:20]))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	engineers, err := c.engineers.fetchAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(engineers) != 1 || hits.Load() != 2 {
+		t.Errorf("expected the truncated page to be fetched again, got %+v after %d requests", engineers, hits.Load())
+	}
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Default circuit breaker settings.
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without contacting the API while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// WithCircuitBreaker makes the client stop sending requests for cooldown
+// once threshold consecutive requests failed with a transport error or a
+// server error after exhausting their retries. After the cooldown a single request is let through to
+// probe whether the API recovered. A threshold of zero disables the
+// breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		if threshold <= 0 {
+			c.breaker = nil
+			return
+		}
+		c.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown}
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker is shared by every request of a client, so once the API
+// is known to be down all resources fail fast instead of each waiting for
+// its own timeouts and retries.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openUntil time.Time
+	lastErr   error
+}
+
+// allow reports whether a request may be sent, returning the error to fail
+// fast with otherwise. Once the cooldown is over, the first caller becomes
+// the probe and others keep failing until its outcome is recorded.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Now().Before(b.openUntil) {
+			return b.openError()
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		return b.openError()
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the final outcome of an allowed request.
+func (b *circuitBreaker) record(ctx context.Context, req *http.Request, res *http.Response, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if req.Context().Err() != nil {
+		// A cancelled request says nothing about the API. Let the next
+		// request probe again if this one was the probe.
+		if b.state == breakerHalfOpen {
+			b.state = breakerOpen
+			b.openUntil = time.Now()
+		}
+		return
+	}
+
+	if !endpointFailed(req, res, err) {
+		if b.state != breakerClosed {
+			tflog.SubsystemInfo(ctx, logSubsystem, "DevOps API recovered, closing circuit breaker")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		b.lastErr = nil
+		return
+	}
+
+	b.failures++
+	b.lastErr = err
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openUntil = time.Now().Add(b.cooldown)
+		tflog.SubsystemWarn(ctx, logSubsystem, "DevOps API keeps failing, opening circuit breaker", map[string]interface{}{
+			"consecutive_failures": b.failures,
+			"cooldown":             b.cooldown.String(),
+			"error":                err.Error(),
+		})
+	}
+}
+
+// openError explains why a request was not sent. b.mu must be held.
+func (b *circuitBreaker) openError() error {
+	wait := "until " + b.openUntil.Format(time.RFC3339)
+	if b.state == breakerHalfOpen {
+		wait = "while a single request checks whether it recovered"
+	}
+	return fmt.Errorf("%w: the DevOps API failed %d consecutive requests, so requests are not sent %s; last error: %v",
+		ErrCircuitOpen, b.failures, wait, b.lastErr)
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL,
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1}),
+		WithCircuitBreaker(2, 50*time.Millisecond),
+	)
+	ctx := context.Background()
+
+	// Each request counts once, after its retries ran out.
+	for range 2 {
+		var apiErr *APIError
+		if _, err := c.engineers.fetchAll(ctx); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("expected the 503, got %v", err)
+		}
+	}
+	if got := hits.Load(); got != 4 {
+		t.Errorf("expected 4 requests before the breaker opened, got %d", got)
+	}
+
+	// While open, requests fail fast without reaching the API.
+	_, err := c.developers.fetchAll(ctx)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if hits.Load() != 4 {
+		t.Errorf("expected no request while open, got %d", hits.Load())
+	}
+
+	// After the cooldown a single probe closes the breaker again.
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := c.developers.fetchAll(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.developers.fetchAll(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestCircuitBreakerRidesOutShortOutage(t *testing.T) {
+	outageEnd := time.Now().Add(100 * time.Millisecond)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A redeploy answers every request with 503 for a moment.
+		if time.Now().Before(outageEnd) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	// The default breaker with as many parallel requests as Terraform
+	// sends by default.
+	c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{MaxRetries: 3, WaitMin: 50 * time.Millisecond, WaitMax: 200 * time.Millisecond}))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = c.engineers.fetchAll(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("request %d: unexpected error: %s", i, err)
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	b := &circuitBreaker{threshold: 1, cooldown: time.Millisecond}
+	req := httptest.NewRequest(http.MethodGet, "http://devops.invalid/engineers", nil)
+	ctx := context.Background()
+
+	b.record(ctx, req, nil, errors.New("connection refused"))
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open breaker, got %v", err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected the probe to be allowed, got %s", err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected other requests to wait for the probe, got %v", err)
+	}
+
+	// A failed probe reopens the breaker for another cooldown.
+	b.record(ctx, req, &http.Response{StatusCode: http.StatusBadGateway}, errors.New("bad gateway"))
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the breaker to reopen, got %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithCircuitBreaker(1, time.Hour))
+	for range 3 {
+		if _, err := c.engineers.fetchOne(context.Background(), "1"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// collectionCache holds one API collection for the lifetime of the
+// provider process, which Terraform starts once per command. The first
+// caller fetches the collection while concurrent callers wait for that
+// result instead of issuing their own requests. Writes made through the
+// client patch the cached copy so reads after writes stay correct.
+type collectionCache[T any] struct {
+	id func(T) string
+
+	mu       sync.Mutex
+	items    []T
+	loaded   bool
+	inflight *cacheCall
+
+	// generation changes on invalidation and on writes that arrive while
+	// nothing is loaded, so a fetch that raced with them is not cached.
+	generation uint64
+}
+
+// cacheCall tracks a fetch that is in progress.
+type cacheCall struct {
+	done chan struct{}
+	err  error
+}
+
+func newCollectionCache[T any](id func(T) string) *collectionCache[T] {
+	return &collectionCache[T]{id: id}
+}
+
+// list returns the cached collection, calling fetch to populate it if
+// needed.
+func (cc *collectionCache[T]) list(ctx context.Context, fetch func(context.Context) ([]T, error)) ([]T, error) {
+	for {
+		cc.mu.Lock()
+		if cc.loaded {
+			items := slices.Clone(cc.items)
+			cc.mu.Unlock()
+			return items, nil
+		}
+
+		if call := cc.inflight; call != nil {
+			cc.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-call.done:
+			}
+
+			// A leading caller that was cancelled says nothing about
+			// this caller, so only share real failures.
+			if call.err != nil && !errors.Is(call.err, context.Canceled) && !errors.Is(call.err, context.DeadlineExceeded) {
+				return nil, call.err
+			}
+			continue
+		}
+
+		call := &cacheCall{done: make(chan struct{})}
+		cc.inflight = call
+		generation := cc.generation
+		cc.mu.Unlock()
+
+		items, err := fetch(ctx)
+
+		cc.mu.Lock()
+		cc.inflight = nil
+		if err == nil && generation == cc.generation {
+			cc.items = items
+			cc.loaded = true
+		}
+		cc.mu.Unlock()
+
+		call.err = err
+		close(call.done)
+
+		if err != nil {
+			return nil, err
+		}
+		return slices.Clone(items), nil
+	}
+}
+
+// get returns the item with the given ID from the cached collection.
+func (cc *collectionCache[T]) get(ctx context.Context, id string, fetch func(context.Context) ([]T, error)) (*T, error) {
+	items, err := cc.list(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if cc.id(item) == id {
+			return &item, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// cached returns the item with the given ID without fetching anything.
+// The second result reports whether the collection is loaded at all.
+func (cc *collectionCache[T]) cached(id string) (*T, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if !cc.loaded {
+		return nil, false
+	}
+
+	for _, item := range cc.items {
+		if cc.id(item) == id {
+			return &item, true
+		}
+	}
+
+	return nil, true
+}
+
+// ids returns the IDs of the cached items without fetching anything. The
+// second result reports whether the collection is loaded at all.
+func (cc *collectionCache[T]) ids() (map[string]bool, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if !cc.loaded {
+		return nil, false
+	}
+
+	ids := make(map[string]bool, len(cc.items))
+	for _, item := range cc.items {
+		ids[cc.id(item)] = true
+	}
+
+	return ids, true
+}
+
+// upsert records a created or updated item.
+func (cc *collectionCache[T]) upsert(item T) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if !cc.loaded {
+		cc.generation++
+		return
+	}
+
+	id := cc.id(item)
+	for i := range cc.items {
+		if cc.id(cc.items[i]) == id {
+			cc.items[i] = item
+			return
+		}
+	}
+	cc.items = append(cc.items, item)
+}
+
+// remove drops a deleted item.
+func (cc *collectionCache[T]) remove(id string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if !cc.loaded {
+		cc.generation++
+		return
+	}
+
+	cc.items = slices.DeleteFunc(cc.items, func(item T) bool {
+		return cc.id(item) == id
+	})
+}
+
+// invalidate discards the cached collection so the next read fetches it
+// again.
+func (cc *collectionCache[T]) invalidate() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.items = nil
+	cc.loaded = false
+	cc.generation++
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetEngineerFetchesCollectionOnce(t *testing.T) {
+	var lists atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path != "/engineers":
+			// Older API versions have no single-item route.
+			http.NotFound(w, r)
+		case r.Method == http.MethodGet:
+			lists.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			_, _ = w.Write([]byte(`[{"id":"1","name":"Ada","email":"ada@example.com"},{"id":"2","name":"Grace","email":"grace@example.com"}]`))
+		case r.Method == http.MethodPost:
+			_, _ = w.Write([]byte(`{"id":"3","name":"Linus","email":"linus@example.com"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetEngineer(ctx, "2"); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := lists.Load(); got != 1 {
+		t.Fatalf("expected 1 collection fetch, got %d", got)
+	}
+
+	if _, err := c.CreateEngineer(ctx, Engineer{Name: "Linus", Email: "linus@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if engineer, err := c.GetEngineer(ctx, "3"); err != nil || engineer.Name != "Linus" {
+		t.Fatalf("expected created engineer from cache, got %+v, %v", engineer, err)
+	}
+
+	if err := c.DeleteEngineer(ctx, "1", ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.GetEngineer(ctx, "1"); err == nil {
+		t.Fatal("expected deleted engineer to be gone")
+	}
+
+	if got := lists.Load(); got != 1 {
+		t.Fatalf("expected writes to patch the cache, got %d collection fetches", got)
+	}
+}
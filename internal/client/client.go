@@ -1,29 +1,80 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
-	"strings"
+	"net/url"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 // Client represents the DevOps API client
 type Client struct {
 	HostURL    string
 	HTTPClient *http.Client
+
+	baseURL        *url.URL
+	extraEndpoints []string
+	endpoints      []*endpoint
+	cooldown       time.Duration
+
+	retry    RetryPolicy
+	token    string
+	username string
+	password string
+
+	userAgent string
+	headers   http.Header
+
+	engineers  *Collection[Engineer, EngineerPatch]
+	developers *Collection[Developer, DeveloperPatch]
+	ops        *Collection[Ops, OpsPatch]
+	devOps     *Collection[DevOps, DevOpsPatch]
+
+	apiVersion string
+
+	// idempotencyKeys cannot be detected from a request, so it stays
+	// unknown, and creates are not retried, unless Probe learns otherwise.
+	idempotencyKeys routeSupport
+
+	pageSize         int
+	maxResponseBytes int64
+
+	breaker *circuitBreaker
+	metrics *Metrics
+	tracer  trace.Tracer
+
+	limiter *rate.Limiter
+	slots   chan struct{}
 }
 
-// ErrNotFound indicates a requested resource could not be located.
-var ErrNotFound = errors.New("resource not found")
+// Option configures optional Client behaviour.
+type Option func(*Client)
+
+// WithRetryPolicy overrides the default retry policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
 
 // Engineer represents an individual engineer
 type Engineer struct {
 	ID    string `json:"id"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
+
+	// Version is the item revision on APIs that send one in the body
+	Version string `json:"version,omitempty"`
+	// ETag makes updates and deletes conditional, see Collection
+	ETag string `json:"-"`
 }
 
 // Developer represents a collection of developer engineers
@@ -31,252 +82,437 @@ type Developer struct {
 	ID        string     `json:"id"`
 	Name      string     `json:"name"`
 	Engineers []Engineer `json:"engineers"`
+
+	// Version is the item revision on APIs that send one in the body
+	Version string `json:"version,omitempty"`
+	// ETag makes updates and deletes conditional, see Collection
+	ETag string `json:"-"`
 }
 
-// NewClient creates a new DevOps API client
-func NewClient(host string) (*Client, error) {
-	c := Client{
-		HTTPClient: &http.Client{Timeout: 10 * time.Second},
-		HostURL:    host,
-	}
+// Ops represents a collection of operations engineers
+type Ops struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Engineers []Engineer `json:"engineers"`
 
-	return &c, nil
+	// Version is the item revision on APIs that send one in the body
+	Version string `json:"version,omitempty"`
+	// ETag makes updates and deletes conditional, see Collection
+	ETag string `json:"-"`
 }
 
-// doRequest performs HTTP requests to the API
-func (c *Client) doRequest(req *http.Request) ([]byte, error) {
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+// DevOps represents a combination of developer and operations teams
+type DevOps struct {
+	ID  string      `json:"id"`
+	Dev []Developer `json:"dev"`
+	Ops []Ops       `json:"ops"`
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
+	// Version is the item revision on APIs that send one in the body
+	Version string `json:"version,omitempty"`
+	// ETag makes updates and deletes conditional, see Collection
+	ETag string `json:"-"`
+}
 
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
-	}
+// EngineerPatch lists the engineer fields to change in a partial update.
+// Nil fields are left untouched on the server.
+type EngineerPatch struct {
+	Name  *string `json:"name,omitempty"`
+	Email *string `json:"email,omitempty"`
 
-	return body, err
+	// ETag makes the patch conditional, as for Engineer.ETag.
+	ETag string `json:"-"`
 }
 
-// GetEngineers retrieves all engineers
-func (c *Client) GetEngineers() ([]Engineer, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/engineers", c.HostURL), nil)
-	if err != nil {
-		return nil, err
-	}
+// DeveloperPatch lists the developer fields to change in a partial update.
+// Nil fields are left untouched on the server, so a rename never touches
+// team membership.
+type DeveloperPatch struct {
+	Name *string `json:"name,omitempty"`
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
+	// ETag makes the patch conditional, as for Developer.ETag.
+	ETag string `json:"-"`
+}
 
-	var engineers []Engineer
-	err = json.Unmarshal(body, &engineers)
-	if err != nil {
-		return nil, err
-	}
+// OpsPatch lists the ops team fields to change in a partial update. Nil
+// fields are left untouched on the server.
+type OpsPatch struct {
+	Name *string `json:"name,omitempty"`
 
-	return engineers, nil
+	// ETag makes the patch conditional, as for Ops.ETag.
+	ETag string `json:"-"`
 }
 
-// GetEngineer retrieves a specific engineer by ID
-// Since the API doesn't support individual engineer retrieval,
-// we get all engineers and filter by ID
-func (c *Client) GetEngineer(engineerID string) (*Engineer, error) {
-	engineers, err := c.GetEngineers()
-	if err != nil {
-		return nil, err
+// DevOpsPatch lists the DevOps group fields to change in a partial update.
+// Nil fields are left untouched on the server; a set field replaces the
+// whole list, as merge patches do not merge arrays.
+type DevOpsPatch struct {
+	Dev *[]Developer `json:"dev,omitempty"`
+	Ops *[]Ops       `json:"ops,omitempty"`
+
+	// ETag makes the patch conditional, as for DevOps.ETag.
+	ETag string `json:"-"`
+}
+
+// mergePatchContentType is the media type of RFC 7396 JSON merge patches.
+const mergePatchContentType = "application/merge-patch+json"
+
+// WithTLSConfig makes the client use a transport configured with the given
+// TLS settings, such as a private CA pool or client certificates.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		transport := c.transport()
+		transport.TLSClientConfig = config
+		c.HTTPClient.Transport = transport
 	}
+}
 
-	for _, engineer := range engineers {
-		if engineer.ID == engineerID {
-			return &engineer, nil
-		}
+// WithTransport makes the client send requests through rt, for example a
+// cassette recorder in tests. It replaces any transport set by earlier
+// options, so it should come last.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.HTTPClient.Transport = rt
 	}
+}
 
-	return nil, fmt.Errorf("%w: engineer with ID %s not found", ErrNotFound, engineerID)
+// transport returns a copy of the client's current transport, falling back
+// to a copy of http.DefaultTransport, so options can adjust it safely.
+func (c *Client) transport() *http.Transport {
+	if t, ok := c.HTTPClient.Transport.(*http.Transport); ok {
+		return t.Clone()
+	}
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		return t.Clone()
+	}
+	return &http.Transport{Proxy: http.ProxyFromEnvironment}
 }
 
-// CreateEngineer creates a new engineer
-func (c *Client) CreateEngineer(engineer Engineer) (*Engineer, error) {
-	rb, err := json.Marshal(engineer)
+// NewClient creates a new DevOps API client
+func NewClient(host string, opts ...Option) (*Client, error) {
+	baseURL, err := ParseEndpoint(host)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid endpoint %q: %w", host, err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/engineers", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
+	c := Client{
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		HostURL:    baseURL.String(),
+		baseURL:    baseURL,
+		retry:      DefaultRetryPolicy(),
+		cooldown:   DefaultEndpointCooldown,
+
+		maxResponseBytes: DefaultMaxResponseBytes,
+		breaker:          &circuitBreaker{threshold: DefaultBreakerThreshold, cooldown: DefaultBreakerCooldown},
+		tracer:           defaultTracer(),
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	c.engineers = newCollection(&c, engineersCollection)
+	c.developers = newCollection(&c, developersCollection)
+	c.ops = newCollection(&c, opsCollection)
+	c.devOps = newCollection(&c, devOpsCollection)
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+	// Teams embed their engineers and DevOps groups embed their teams, so
+	// changing a member makes the cached copies of its groups stale.
+	c.engineers.dependents = []invalidator{c.developers, c.ops, c.devOps}
+	c.developers.dependents = []invalidator{c.devOps}
+	c.ops.dependents = []invalidator{c.devOps}
+
+	for _, opt := range opts {
+		opt(&c)
 	}
 
-	var newEngineer Engineer
-	err = json.Unmarshal(body, &newEngineer)
-	if err != nil {
-		return nil, err
+	c.endpoints = []*endpoint{{base: baseURL}}
+	for _, extra := range c.extraEndpoints {
+		extraURL, err := ParseEndpoint(extra)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", extra, err)
+		}
+		c.endpoints = append(c.endpoints, &endpoint{base: extraURL})
 	}
 
-	return &newEngineer, nil
+	return &c, nil
 }
 
-// UpdateEngineer updates an existing engineer
-func (c *Client) UpdateEngineer(engineerID string, engineer Engineer) (*Engineer, error) {
-	rb, err := json.Marshal(engineer)
-	if err != nil {
-		return nil, err
-	}
+// doRequest performs HTTP requests to the API, retrying transient
+// failures according to the client's retry policy.
+func (c *Client) doRequest(req *http.Request) ([]byte, error) {
+	_, body, err := c.doRequestWithResponse(req)
+	return body, err
+}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/engineers/%s", c.HostURL, engineerID), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
+// doRequestWithResponse is doRequest for callers that also need the
+// response headers of the final attempt.
+func (c *Client) doRequestWithResponse(req *http.Request) (*http.Response, []byte, error) {
+	return c.send(req, nil)
+}
 
-	req.Header.Set("Content-Type", "application/json")
+// doRequestDecode is doRequestWithResponse for large responses: instead of
+// buffering a successful body, it hands the body to decode as it streams
+// in. Error responses are still read whole to build the APIError.
+func (c *Client) doRequestDecode(req *http.Request, decode decodeFunc) (*http.Response, error) {
+	res, _, err := c.send(req, decode)
+	return res, err
+}
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
+// send runs the retry loop for req. See do for the meaning of decode.
+func (c *Client) send(req *http.Request, decode decodeFunc) (*http.Response, []byte, error) {
+	c.setHeaders(req)
+	c.setAuth(req)
+	req, d := trackDelivery(req)
+	ctx := c.logContext(req.Context())
+
+	// The breaker admits and judges whole requests, retries included, so
+	// a short outage that retries ride out neither opens it nor fails
+	// requests that are already waiting to retry.
+	if err := c.breaker.allow(); err != nil {
+		return nil, nil, err
+	}
+	var lastRes *http.Response
+	var lastErr error
+	defer func() {
+		c.breaker.record(ctx, req, lastRes, lastErr)
+	}()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := rewind(req); err != nil {
+				return nil, nil, err
+			}
+			if d.wasWritten() {
+				d.markResent()
+			}
+			c.metrics.retried(req, c.route(req.URL))
+		}
 
-	var updatedEngineer Engineer
-	err = json.Unmarshal(body, &updatedEngineer)
-	if err != nil {
-		return nil, err
+		res, body, err := c.doWithFailover(ctx, req, attempt, decode)
+		lastRes, lastErr = res, err
+		if attempt >= c.retry.MaxRetries || !shouldRetry(req, res, err) {
+			if attempt > 0 && err != nil {
+				err = fmt.Errorf("giving up after %d attempts: %w", attempt+1, err)
+			}
+			return res, body, err
+		}
+
+		if err := sleep(req.Context(), c.retry.backoff(attempt, res)); err != nil {
+			return nil, nil, err
+		}
 	}
+}
 
-	return &updatedEngineer, nil
+// do performs a single attempt of req, returning the response together
+// with its fully read body. When decode is set, a successful body is
+// passed to it instead and no body is returned.
+func (c *Client) do(ctx context.Context, req *http.Request, attempt int, decode decodeFunc) (*http.Response, []byte, error) {
+	ctx, span := c.startSpan(ctx, req, attempt)
+	res, body, err := c.doAttempt(ctx, req, attempt, decode)
+	endSpan(span, res, err)
+
+	return res, body, err
 }
 
-// DeleteEngineer deletes an engineer
-func (c *Client) DeleteEngineer(engineerID string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/engineers/%s", c.HostURL, engineerID), nil)
+// doAttempt is do without the tracing span.
+func (c *Client) doAttempt(ctx context.Context, req *http.Request, attempt int, decode decodeFunc) (*http.Response, []byte, error) {
+	release, err := c.acquire(ctx)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
+	defer release()
+
+	logRequest(ctx, req, attempt)
+	start := time.Now()
 
-	_, err = c.doRequest(req)
+	res, err := c.HTTPClient.Do(req)
+	c.metrics.observe(req, c.route(req.URL), res, err, time.Since(start))
 	if err != nil {
-		return err
+		logResponse(ctx, req, nil, nil, err, attempt, time.Since(start))
+		return nil, nil, err
 	}
+	defer res.Body.Close()
 
-	return nil
-}
-
-// GetDevelopers retrieves all developers
-func (c *Client) GetDevelopers() ([]Developer, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/dev", c.HostURL), nil)
+	reader, err := c.responseBody(req, res)
 	if err != nil {
-		return nil, err
+		logResponse(ctx, req, nil, nil, err, attempt, time.Since(start))
+		return nil, nil, err
+	}
+
+	success := res.StatusCode >= 200 && res.StatusCode < 300
+	if success && decode != nil {
+		logResponse(ctx, req, res, nil, nil, attempt, time.Since(start))
+		if err := decode(res, reader); err != nil {
+			if invalidBody(err) {
+				return res, nil, err
+			}
+			// The body broke off in transit, so the attempt failed like
+			// any other transport error and may be retried elsewhere.
+			logResponse(ctx, req, nil, nil, err, attempt, time.Since(start))
+			return nil, nil, err
+		}
+		return res, nil, nil
 	}
 
-	body, err := c.doRequest(req)
+	body, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, err
+		logResponse(ctx, req, nil, nil, err, attempt, time.Since(start))
+		if errors.Is(err, ErrResponseTooLarge) {
+			// Not a transport failure: sending the request again would
+			// only produce the same oversized response.
+			return res, nil, err
+		}
+		return nil, nil, err
 	}
 
-	var developers []Developer
-	err = json.Unmarshal(body, &developers)
-	if err != nil {
-		return nil, err
+	logResponse(ctx, req, res, body, nil, attempt, time.Since(start))
+
+	if !success {
+		return res, nil, newAPIError(req, res, body)
 	}
 
-	return developers, nil
+	return res, body, nil
 }
 
-// GetDeveloper retrieves a specific developer by ID
-func (c *Client) GetDeveloper(developerID string) (*Developer, error) {
-	developers, err := c.GetDevelopers()
-	if err != nil {
-		return nil, err
-	}
+// engineersCollection declares the /engineers collection.
+var engineersCollection = collectionDef[Engineer, EngineerPatch]{
+	path:      "engineers",
+	kind:      "engineer",
+	id:        func(e Engineer) string { return e.ID },
+	etag:      func(e Engineer) string { return e.ETag },
+	setETag:   func(e *Engineer, etag string) { e.ETag = etag },
+	patchETag: func(p EngineerPatch) string { return p.ETag },
+	version:   func(e Engineer) string { return e.Version },
+	sameItem:  sameEngineer,
+	uniqueKey: true,
+}
 
-	for _, developer := range developers {
-		if developer.ID == developerID {
-			return &developer, nil
-		}
-	}
+// developersCollection declares the /dev collection.
+var developersCollection = collectionDef[Developer, DeveloperPatch]{
+	path:      "dev",
+	kind:      "developer",
+	id:        func(d Developer) string { return d.ID },
+	etag:      func(d Developer) string { return d.ETag },
+	setETag:   func(d *Developer, etag string) { d.ETag = etag },
+	patchETag: func(p DeveloperPatch) string { return p.ETag },
+	version:   func(d Developer) string { return d.Version },
+	sameItem:  sameDeveloper,
+}
 
-	return nil, fmt.Errorf("%w: developer with ID %s not found", ErrNotFound, developerID)
+// opsCollection declares the /ops collection.
+var opsCollection = collectionDef[Ops, OpsPatch]{
+	path:      "ops",
+	kind:      "ops team",
+	id:        func(o Ops) string { return o.ID },
+	etag:      func(o Ops) string { return o.ETag },
+	setETag:   func(o *Ops, etag string) { o.ETag = etag },
+	patchETag: func(p OpsPatch) string { return p.ETag },
+	version:   func(o Ops) string { return o.Version },
+	sameItem:  sameOps,
 }
 
-// CreateDeveloper creates a new developer
-func (c *Client) CreateDeveloper(developer Developer) (*Developer, error) {
-	rb, err := json.Marshal(developer)
-	if err != nil {
-		return nil, err
-	}
+// devOpsCollection declares the /devops collection. DevOps groups have no
+// natural key, so a create whose response is lost can only be recovered by
+// sending it again, not by searching for the group.
+var devOpsCollection = collectionDef[DevOps, DevOpsPatch]{
+	path:      "devops",
+	kind:      "devops group",
+	id:        func(d DevOps) string { return d.ID },
+	etag:      func(d DevOps) string { return d.ETag },
+	setETag:   func(d *DevOps, etag string) { d.ETag = etag },
+	patchETag: func(p DevOpsPatch) string { return p.ETag },
+	version:   func(d DevOps) string { return d.Version },
+}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/dev", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
+// Ops returns the client for the /ops collection.
+func (c *Client) Ops() *Collection[Ops, OpsPatch] {
+	return c.ops
+}
 
-	req.Header.Set("Content-Type", "application/json")
+// DevOps returns the client for the /devops collection.
+func (c *Client) DevOps() *Collection[DevOps, DevOpsPatch] {
+	return c.devOps
+}
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
+// GetEngineers retrieves all engineers. The collection is fetched once and
+// then served from the client's cache.
+func (c *Client) GetEngineers(ctx context.Context) ([]Engineer, error) {
+	return c.engineers.List(ctx)
+}
 
-	var newDeveloper Developer
-	err = json.Unmarshal(body, &newDeveloper)
-	if err != nil {
-		return nil, err
-	}
+// Engineers streams all engineers, following the API's pagination. Unlike
+// GetEngineers it always reads from the API and does not use the cache.
+func (c *Client) Engineers(ctx context.Context) iter.Seq2[Engineer, error] {
+	return c.engineers.All(ctx)
+}
 
-	return &newDeveloper, nil
+// GetEngineer retrieves a specific engineer by ID
+// Older API versions don't support individual engineer retrieval,
+// in which case we look the engineer up in the cached collection
+func (c *Client) GetEngineer(ctx context.Context, engineerID string) (*Engineer, error) {
+	return c.engineers.Get(ctx, engineerID)
 }
 
-// UpdateDeveloper updates an existing developer
-func (c *Client) UpdateDeveloper(developerID string, developer Developer) (*Developer, error) {
-	rb, err := json.Marshal(developer)
-	if err != nil {
-		return nil, err
-	}
+// CreateEngineer creates a new engineer. When the outcome is unknown the
+// engineer is looked up by email before giving up.
+func (c *Client) CreateEngineer(ctx context.Context, engineer Engineer) (*Engineer, error) {
+	return c.engineers.Create(ctx, engineer)
+}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/dev/%s", c.HostURL, developerID), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
+// UpdateEngineer updates an existing engineer
+func (c *Client) UpdateEngineer(ctx context.Context, engineerID string, engineer Engineer) (*Engineer, error) {
+	return c.engineers.Update(ctx, engineerID, engineer)
+}
 
-	req.Header.Set("Content-Type", "application/json")
+// PatchEngineer applies a JSON merge patch to an existing engineer,
+// changing only the fields set in patch. It returns ErrPatchNotSupported
+// without changing anything when the API does not accept PATCH requests,
+// in which case callers should fall back to UpdateEngineer.
+func (c *Client) PatchEngineer(ctx context.Context, engineerID string, patch EngineerPatch) (*Engineer, error) {
+	return c.engineers.Patch(ctx, engineerID, patch)
+}
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
+// DeleteEngineer deletes an engineer. A non-empty etag makes the
+// delete conditional on the engineer being unchanged on the server.
+func (c *Client) DeleteEngineer(ctx context.Context, engineerID string, etag string) error {
+	return c.engineers.Delete(ctx, engineerID, etag)
+}
 
-	var updatedDeveloper Developer
-	err = json.Unmarshal(body, &updatedDeveloper)
-	if err != nil {
-		return nil, err
-	}
+// GetDevelopers retrieves all developers. The collection is fetched once
+// and then served from the client's cache.
+func (c *Client) GetDevelopers(ctx context.Context) ([]Developer, error) {
+	return c.developers.List(ctx)
+}
 
-	return &updatedDeveloper, nil
+// Developers streams all developers, following the API's pagination. Unlike
+// GetDevelopers it always reads from the API and does not use the cache.
+func (c *Client) Developers(ctx context.Context) iter.Seq2[Developer, error] {
+	return c.developers.All(ctx)
 }
 
-// DeleteDeveloper deletes a developer
-func (c *Client) DeleteDeveloper(developerID string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/dev/%s", c.HostURL, developerID), nil)
-	if err != nil {
-		return err
-	}
+// GetDeveloper retrieves a specific developer by ID, falling back to the
+// cached collection on API versions without individual retrieval
+func (c *Client) GetDeveloper(ctx context.Context, developerID string) (*Developer, error) {
+	return c.developers.Get(ctx, developerID)
+}
 
-	_, err = c.doRequest(req)
-	if err != nil {
-		return err
-	}
+// CreateDeveloper creates a new developer. When the outcome is unknown the
+// team is looked up by name before giving up.
+func (c *Client) CreateDeveloper(ctx context.Context, developer Developer) (*Developer, error) {
+	return c.developers.Create(ctx, developer)
+}
+
+// UpdateDeveloper updates an existing developer
+func (c *Client) UpdateDeveloper(ctx context.Context, developerID string, developer Developer) (*Developer, error) {
+	return c.developers.Update(ctx, developerID, developer)
+}
+
+// PatchDeveloper applies a JSON merge patch to an existing developer,
+// changing only the fields set in patch. It returns ErrPatchNotSupported
+// without changing anything when the API does not accept PATCH requests,
+// in which case callers should fall back to UpdateDeveloper.
+func (c *Client) PatchDeveloper(ctx context.Context, developerID string, patch DeveloperPatch) (*Developer, error) {
+	return c.developers.Patch(ctx, developerID, patch)
+}
 
-	return nil
+// DeleteDeveloper deletes a developer. A non-empty etag makes the
+// delete conditional on the developer being unchanged on the server.
+func (c *Client) DeleteDeveloper(ctx context.Context, developerID string, etag string) error {
+	return c.developers.Delete(ctx, developerID, etag)
 }
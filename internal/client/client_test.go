@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestUpdateEngineerIfMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada@example.com"}`))
+		case http.MethodPut:
+			if r.Header.Get("If-Match") != `"v1"` {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			w.Header().Set("ETag", `"v2"`)
+			_, _ = w.Write([]byte(`{"id":"1","name":"Ada L.","email":"ada@example.com"}`))
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+	ctx := context.Background()
+
+	engineer, err := c.GetEngineer(ctx, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if engineer.ETag != `"v1"` {
+		t.Fatalf("expected ETag to be captured, got %q", engineer.ETag)
+	}
+
+	engineer.Name = "Ada L."
+	updated, err := c.UpdateEngineer(ctx, "1", *engineer)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if updated.ETag != `"v2"` {
+		t.Fatalf("expected new ETag, got %q", updated.ETag)
+	}
+
+	engineer.ETag = `"stale"`
+	if _, err := c.UpdateEngineer(ctx, "1", *engineer); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestListedItemsUseVersionAsETag(t *testing.T) {
+	var ifMatch atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/dev":
+			_, _ = w.Write([]byte(`[{"id":"1","name":"Frontend","engineers":[],"version":"7"}]`))
+		case r.Method == http.MethodPut:
+			ifMatch.Store(r.Header.Get("If-Match"))
+			_, _ = w.Write([]byte(`{"id":"1","name":"Web","engineers":[],"version":"8"}`))
+		default:
+			// A list-only API without single-item reads.
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+	ctx := context.Background()
+
+	developer, err := c.GetDeveloper(ctx, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if developer.ETag != `"7"` {
+		t.Fatalf("expected the version as ETag, got %q", developer.ETag)
+	}
+
+	developer.Name = "Web"
+	updated, err := c.UpdateDeveloper(ctx, "1", *developer)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := ifMatch.Load(); got != `"7"` {
+		t.Errorf("expected If-Match from the version, got %v", got)
+	}
+	if updated.ETag != `"8"` {
+		t.Errorf("expected the new version as ETag, got %q", updated.ETag)
+	}
+}
+
+func TestPatchDeveloper(t *testing.T) {
+	var patches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		patches.Add(1)
+		if r.Header.Get("Content-Type") != "application/merge-patch+json" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"Platform"}` {
+			t.Errorf("expected only the name in the patch, got %s", body)
+		}
+		_, _ = w.Write([]byte(`{"id":"7","name":"Platform","engineers":[{"id":"1","name":"Ada","email":"ada@example.com"}]}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+
+	name := "Platform"
+	developer, err := c.PatchDeveloper(context.Background(), "7", DeveloperPatch{Name: &name})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(developer.Engineers) != 1 {
+		t.Fatalf("expected membership to be untouched, got %+v", developer.Engineers)
+	}
+}
+
+func TestPatchEngineerNotSupported(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+
+	name := "Ada"
+	for i := 0; i < 2; i++ {
+		if _, err := c.PatchEngineer(context.Background(), "1", EngineerPatch{Name: &name}); !errors.Is(err, ErrPatchNotSupported) {
+			t.Fatalf("expected ErrPatchNotSupported, got %v", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected PATCH support to be remembered, got %d requests", got)
+	}
+}
@@ -0,0 +1,387 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Collection is a typed client for one collection of the DevOps API, such
+// as /engineers. T is the item type and P the type of its JSON merge
+// patches. Every request shares the client's retries, failover, logging and
+// error handling, and reads are served from a per-run cache that writes
+// keep current.
+//
+// Items keep the ETag the API returned with them, and an item or patch
+// with an ETag is only written if it is unchanged on the server. APIs that
+// version items in the body rather than with ETag headers, which also
+// covers items that are only ever listed, get an ETag derived from that
+// version.
+type Collection[T any, P any] struct {
+	client *Client
+	def    collectionDef[T, P]
+	cache  *collectionCache[T]
+
+	byID  routeSupport
+	patch routeSupport
+
+	// dependents embed items of this collection, so their cached copies
+	// go stale when one of these items changes.
+	dependents []invalidator
+}
+
+// collectionDef declares an API collection. Supporting another entity of
+// the API takes a Go type for it and one of these.
+type collectionDef[T any, P any] struct {
+	// path is the collection path below the endpoint, such as "engineers".
+	path string
+	// kind names a single item in errors and logs, such as "engineer".
+	kind string
+
+	id        func(T) string
+	etag      func(T) string
+	setETag   func(*T, string)
+	patchETag func(P) string
+
+	// version, when set, reads the per-item version of APIs that send one
+	// in the body. It stands in for the ETag wherever no ETag header came
+	// with the item, such as in a listed collection.
+	version func(T) string
+
+	// sameItem, when set, matches the item a create of want would have
+	// produced by its natural key, so a create whose response was lost
+	// can be recovered.
+	sameItem func(want T) func(T) bool
+
+	// uniqueKey says the API rejects a second item with the natural key of
+	// sameItem as a conflict. A match found after a create whose outcome
+	// is unknown can then only be the created item, so creates need not
+	// list the collection beforehand.
+	uniqueKey bool
+}
+
+// invalidator is a cache that can be told its contents are stale.
+type invalidator interface {
+	invalidate()
+}
+
+func newCollection[T any, P any](c *Client, def collectionDef[T, P]) *Collection[T, P] {
+	return &Collection[T, P]{
+		client: c,
+		def:    def,
+		cache:  newCollectionCache(def.id),
+	}
+}
+
+// List returns every item. The collection is fetched once and then served
+// from the client's cache.
+func (col *Collection[T, P]) List(ctx context.Context) ([]T, error) {
+	return col.cache.list(ctx, col.fetchAll)
+}
+
+// All streams every item, following the API's pagination. Unlike List it
+// always reads from the API and does not use the cache.
+func (col *Collection[T, P]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for item, err := range paginate[T](ctx, col.client, col.client.url(col.def.path)) {
+			if err == nil {
+				col.versionETag(&item)
+			}
+			if !yield(item, err) {
+				return
+			}
+		}
+	}
+}
+
+// Get retrieves a single item by ID. On API versions without single-item
+// routes the item is looked up in the cached collection instead.
+func (col *Collection[T, P]) Get(ctx context.Context, id string) (*T, error) {
+	item, err := lookup(ctx, &col.byID, col.cache, id, col.fetchOne, col.fetchAll)
+	if err != nil {
+		return nil, err
+	}
+	if item != nil {
+		return item, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s with ID %s not found", ErrNotFound, col.def.kind, id)
+}
+
+// Create creates an item. The request carries an Idempotency-Key, and on
+// APIs that advertise honouring it the create is retried like any other
+// request. When the outcome is unknown the create is sent once more under
+// the same key on those APIs, and failing that the item is looked up by
+// its natural key before giving up.
+func (col *Collection[T, P]) Create(ctx context.Context, item T) (*T, error) {
+	req, err := newJSONRequest(ctx, "POST", col.client.url(col.def.path), item, "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	existing := col.snapshot(ctx)
+	req, d := col.client.withIdempotencyKey(req)
+
+	res, body, err := col.client.doRequestWithResponse(req)
+	if err != nil {
+		if !outcomeUnknown(err, d.wasWritten()) {
+			return nil, err
+		}
+		created := col.recoverCreate(ctx, req, item, existing, err)
+		if created == nil {
+			return nil, err
+		}
+		col.cache.upsert(*created)
+		return created, nil
+	}
+
+	created, err := col.decode(res, body)
+	if err != nil {
+		return nil, err
+	}
+
+	col.cache.upsert(*created)
+
+	return created, nil
+}
+
+// Update replaces an item. When the item carries an ETag, the update only
+// succeeds if the item is unchanged on the server.
+func (col *Collection[T, P]) Update(ctx context.Context, id string, item T) (*T, error) {
+	req, err := newJSONRequest(ctx, "PUT", col.client.url(col.def.path, id), item, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	setIfMatch(req, col.def.etag(item))
+	req, d := trackDelivery(req)
+
+	res, body, err := col.client.doRequestWithResponse(req)
+	if err != nil {
+		if current := col.confirmWrite(ctx, d, id, item, err); current != nil {
+			return current, nil
+		}
+		return nil, err
+	}
+
+	updated, err := col.decode(res, body)
+	if err != nil {
+		return nil, err
+	}
+
+	col.cache.upsert(*updated)
+	col.invalidateDependents()
+
+	return updated, nil
+}
+
+// Patch applies a JSON merge patch to an item, changing only the fields
+// set in patch. It returns ErrPatchNotSupported without changing anything
+// when the API does not accept PATCH requests, in which case callers
+// should fall back to Update.
+func (col *Collection[T, P]) Patch(ctx context.Context, id string, patch P) (*T, error) {
+	if col.patch.get() == Unsupported {
+		return nil, ErrPatchNotSupported
+	}
+
+	req, err := newJSONRequest(ctx, "PATCH", col.client.url(col.def.path, id), patch, mergePatchContentType)
+	if err != nil {
+		return nil, err
+	}
+	setIfMatch(req, col.def.patchETag(patch))
+	req, d := trackDelivery(req)
+
+	res, body, err := col.client.doRequestWithResponse(req)
+	if err != nil {
+		if patchUnsupported(err) {
+			col.patch.set(Unsupported)
+			return nil, ErrPatchNotSupported
+		}
+		if current := col.confirmWrite(ctx, d, id, patch, err); current != nil {
+			return current, nil
+		}
+		return nil, err
+	}
+	col.patch.set(Supported)
+
+	patched, err := col.decode(res, body)
+	if err != nil {
+		return nil, err
+	}
+
+	col.cache.upsert(*patched)
+	col.invalidateDependents()
+
+	return patched, nil
+}
+
+// Delete deletes an item. A non-empty etag makes the delete conditional on
+// the item being unchanged on the server. An item that is already gone is
+// the desired outcome and not an error.
+func (col *Collection[T, P]) Delete(ctx context.Context, id string, etag string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", col.client.url(col.def.path, id), nil)
+	if err != nil {
+		return err
+	}
+	setIfMatch(req, etag)
+
+	_, err = col.client.doRequest(req)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	col.cache.remove(id)
+	col.invalidateDependents()
+
+	return nil
+}
+
+// confirmWrite checks whether a conditional update that failed its If-Match
+// check after being sent again was applied by an earlier attempt, whose
+// response was lost. The item is read back and returned when it holds
+// every field of sent; otherwise it returns nil and the caller reports
+// writeErr.
+func (col *Collection[T, P]) confirmWrite(ctx context.Context, d *delivery, id string, sent interface{}, writeErr error) *T {
+	if !errors.Is(writeErr, ErrPreconditionFailed) || !d.wasResent() {
+		return nil
+	}
+
+	col.cache.invalidate()
+	current, err := col.Get(ctx, id)
+	if err != nil || !holdsFields(*current, sent) {
+		return nil
+	}
+
+	tflog.SubsystemInfo(col.client.logContext(ctx), logSubsystem, "Update of the "+col.def.kind+" confirmed by reading it back")
+	col.invalidateDependents()
+	return current
+}
+
+// holdsFields reports whether every JSON field of sent, apart from the ID
+// and version the API assigns, has the same value in item.
+func holdsFields(item interface{}, sent interface{}) bool {
+	var itemFields, sentFields map[string]interface{}
+	if !decodeFields(item, &itemFields) || !decodeFields(sent, &sentFields) {
+		return false
+	}
+
+	for name, value := range sentFields {
+		if name == "id" || name == "version" {
+			continue
+		}
+		if !reflect.DeepEqual(itemFields[name], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeFields converts v to its JSON object form.
+func decodeFields(v interface{}, fields *map[string]interface{}) bool {
+	data, err := json.Marshal(v)
+	return err == nil && json.Unmarshal(data, fields) == nil
+}
+
+// fetchAll downloads every page of the collection.
+func (col *Collection[T, P]) fetchAll(ctx context.Context) ([]T, error) {
+	return collect(col.All(ctx))
+}
+
+// fetchOne downloads a single item from the API.
+func (col *Collection[T, P]) fetchOne(ctx context.Context, id string) (*T, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", col.client.url(col.def.path, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, body, err := col.client.doRequestWithResponse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return col.decode(res, body)
+}
+
+// decode reads an item from a response body, keeping its ETag.
+func (col *Collection[T, P]) decode(res *http.Response, body []byte) (*T, error) {
+	var item T
+	if err := json.Unmarshal(body, &item); err != nil {
+		return nil, err
+	}
+	col.def.setETag(&item, res.Header.Get("ETag"))
+	col.versionETag(&item)
+
+	return &item, nil
+}
+
+// versionETag gives an item without an ETag the one implied by its version
+// field: the version as a strong entity tag.
+func (col *Collection[T, P]) versionETag(item *T) {
+	if col.def.version == nil || col.def.etag(*item) != "" {
+		return
+	}
+	if version := col.def.version(*item); version != "" {
+		col.def.setETag(item, `"`+version+`"`)
+	}
+}
+
+func (col *Collection[T, P]) invalidate() {
+	col.cache.invalidate()
+}
+
+func (col *Collection[T, P]) invalidateDependents() {
+	for _, dependent := range col.dependents {
+		dependent.invalidate()
+	}
+}
+
+// Capabilities returns what the client knows about the server's support
+// for this collection.
+func (col *Collection[T, P]) Capabilities() Capabilities {
+	return Capabilities{
+		APIVersion:      col.client.apiVersion,
+		Patch:           col.patch.get(),
+		SingleItemGet:   col.byID.get(),
+		IdempotencyKeys: col.client.idempotencyKeys.get(),
+	}
+}
+
+// setSupport records known route support, leaving unknown routes to
+// detection on first use.
+func (col *Collection[T, P]) setSupport(byID, patch Support) {
+	if byID != SupportUnknown {
+		col.byID.set(byID)
+	}
+	if patch != SupportUnknown {
+		col.patch.set(patch)
+	}
+}
+
+// newJSONRequest returns a request with v encoded as its JSON body.
+func newJSONRequest(ctx context.Context, method, target string, v interface{}, contentType string) (*http.Request, error) {
+	rb, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return req, nil
+}
+
+// setIfMatch makes req conditional on etag, if there is one.
+func setIfMatch(req *http.Request, etag string) {
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+}
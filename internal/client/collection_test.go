@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEngineerWritesInvalidateGroups(t *testing.T) {
+	var devopsLists atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/devops":
+			devopsLists.Add(1)
+			_, _ = w.Write([]byte(`[{"id":"1","dev":[],"ops":[]}]`))
+		case "/engineers/1":
+			_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada@example.com"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := c.DevOps().List(ctx); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if got := devopsLists.Load(); got != 1 {
+		t.Fatalf("expected one cached fetch, got %d", got)
+	}
+
+	// DevOps groups embed engineers through their teams.
+	if _, err := c.UpdateEngineer(ctx, "1", Engineer{Name: "Ada", Email: "ada@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.DevOps().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := devopsLists.Load(); got != 2 {
+		t.Fatalf("expected a refetch after the engineer changed, got %d", got)
+	}
+}
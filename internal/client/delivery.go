@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+)
+
+// delivery records whether any attempt of one request may have reached the
+// API, across retries and failover. Requests that were never written to a
+// connection can always be sent again; others only when that is safe.
+type delivery struct {
+	written atomic.Bool
+	resent  atomic.Bool
+	keyed   atomic.Bool
+
+	mu       sync.Mutex
+	endpoint *endpoint
+}
+
+type deliveryKey struct{}
+
+// trackDelivery returns req with a delivery attached to its context. A
+// request that already carries one keeps it, so sending it again keeps
+// the history of the earlier sends.
+func trackDelivery(req *http.Request) (*http.Request, *delivery) {
+	if d := deliveryOf(req); d != nil {
+		return req, d
+	}
+
+	d := &delivery{}
+	ctx := context.WithValue(req.Context(), deliveryKey{}, d)
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		// A write that failed partway may still have reached the API, so
+		// it counts as written too.
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			d.written.Store(true)
+		},
+	})
+
+	return req.WithContext(ctx), d
+}
+
+// deliveryOf returns the delivery attached to req, or nil.
+func deliveryOf(req *http.Request) *delivery {
+	d, _ := req.Context().Value(deliveryKey{}).(*delivery)
+	return d
+}
+
+// markWritten records that an attempt reached the API, for transports that
+// do not report writes, such as cassette replays.
+func (d *delivery) markWritten() {
+	if d != nil {
+		d.written.Store(true)
+	}
+}
+
+// wasWritten reports whether any attempt may have reached the API.
+func (d *delivery) wasWritten() bool {
+	return d != nil && d.written.Load()
+}
+
+// markResent records that the request is sent again after an attempt that
+// may have reached the API.
+func (d *delivery) markResent() {
+	d.resent.Store(true)
+}
+
+// wasResent reports whether the request may have reached the API more than
+// once, so a response can describe the effect of an earlier attempt.
+func (d *delivery) wasResent() bool {
+	return d != nil && d.resent.Load()
+}
+
+// markKeyed records that the request carries an Idempotency-Key the client
+// generated for it, so the API applies it at most once however often it
+// is sent.
+func (d *delivery) markKeyed() {
+	d.keyed.Store(true)
+}
+
+// wasKeyed reports whether the request carries a client-generated
+// Idempotency-Key.
+func (d *delivery) wasKeyed() bool {
+	return d != nil && d.keyed.Load()
+}
+
+// pin records that a request which is not idempotent was written to e.
+// Only e knows its Idempotency-Key, so sending it anywhere else could apply
+// it twice. The first endpoint written to is kept.
+func (d *delivery) pin(e *endpoint) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.endpoint == nil {
+		d.endpoint = e
+	}
+}
+
+// pinned returns the endpoint all further attempts must go to, or nil.
+func (d *delivery) pinned() *endpoint {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.endpoint
+}
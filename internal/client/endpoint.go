@@ -0,0 +1,55 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseEndpoint validates a DevOps API endpoint and returns it normalized:
+// an http or https URL with a host and an optional path prefix such as
+// /api/v1, without a trailing slash.
+func ParseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case u.Scheme != "http" && u.Scheme != "https":
+		return nil, fmt.Errorf("scheme must be http or https, got %q", u.Scheme)
+	case u.Host == "":
+		return nil, errors.New("host is missing")
+	case u.User != nil:
+		return nil, errors.New("credentials must not be part of the endpoint")
+	case u.RawQuery != "" || u.ForceQuery:
+		return nil, errors.New("query strings are not supported")
+	case u.Fragment != "":
+		return nil, errors.New("fragments are not supported")
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+
+	return u, nil
+}
+
+// url returns the URL of the API path made of segments below the endpoint,
+// escaping each segment so IDs containing "/" or "?" stay a single path
+// element.
+func (c *Client) url(segments ...string) string {
+	u := *c.baseURL
+	unescaped := u.Path
+	escaped := u.EscapedPath()
+
+	for _, segment := range segments {
+		unescaped += "/" + segment
+		escaped += "/" + url.PathEscape(segment)
+	}
+
+	u.Path = unescaped
+	u.RawPath = escaped
+
+	return u.String()
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	testCases := map[string]struct {
+		endpoint string
+		expected string
+		wantErr  bool
+	}{
+		"host":            {endpoint: "https://devops.example.com", expected: "https://devops.example.com"},
+		"trailing-slash":  {endpoint: "https://devops.example.com/", expected: "https://devops.example.com"},
+		"path-prefix":     {endpoint: "http://localhost:8080/api/v1/", expected: "http://localhost:8080/api/v1"},
+		"escaped-prefix":  {endpoint: "https://devops.example.com/team%2Fa/", expected: "https://devops.example.com/team%2Fa"},
+		"missing-scheme":  {endpoint: "devops.example.com", wantErr: true},
+		"unknown-scheme":  {endpoint: "ftp://devops.example.com", wantErr: true},
+		"missing-host":    {endpoint: "https:///api", wantErr: true},
+		"credentials":     {endpoint: "https://ada:pw@devops.example.com", wantErr: true},
+		"query":           {endpoint: "https://devops.example.com?tenant=acme", wantErr: true},
+		"fragment":        {endpoint: "https://devops.example.com#top", wantErr: true},
+		"malformed":       {endpoint: "https://devops.example.com:port", wantErr: true},
+		"surrounding-ws":  {endpoint: " https://devops.example.com ", expected: "https://devops.example.com"},
+		"empty":           {endpoint: "", wantErr: true},
+		"relative-prefix": {endpoint: "/api/v1", wantErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			u, err := ParseEndpoint(testCase.endpoint)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", u)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if u.String() != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, u)
+			}
+		})
+	}
+}
+
+func TestRequestURLs(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"a/b?c","name":"Ada","email":"ada@example.com"}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL + "/api/v1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.GetEngineer(context.Background(), "a/b?c"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := c.DeleteDeveloper(context.Background(), "team 1", ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"/api/v1/engineers/a%2Fb%3Fc", "/api/v1/dev/team%201"}
+	if len(got) != len(expected) {
+		t.Fatalf("expected requests %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected request to %s, got %s", expected[i], got[i])
+		}
+	}
+}
+
+func TestNewClientInvalidEndpoint(t *testing.T) {
+	if _, err := NewClient("localhost:8080"); err == nil {
+		t.Error("expected error for endpoint without scheme")
+	}
+}
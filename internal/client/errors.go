@@ -0,0 +1,168 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrNotFound indicates a requested resource could not be located.
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrConflict indicates the request conflicts with the current state
+	// of a resource, such as a duplicate email address.
+	ErrConflict = errors.New("resource conflict")
+
+	// ErrValidation indicates the API rejected the request payload.
+	ErrValidation = errors.New("validation failed")
+
+	// ErrPreconditionFailed indicates a conditional update or delete was
+	// rejected because the resource changed since it was last read.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrPatchNotSupported indicates the API does not accept PATCH
+	// requests for a collection, so a full update is required instead.
+	ErrPatchNotSupported = errors.New("partial updates not supported by the API")
+
+	// ErrUnauthorized indicates the API did not accept the configured
+	// credentials, or none were configured.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden indicates the credentials lack permission for the
+	// requested operation.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// FieldError describes a problem with a single field of a request payload.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// APIError is returned when the DevOps API answers with a non-2xx status.
+type APIError struct {
+	StatusCode  int
+	Method      string
+	URL         string
+	Message     string
+	FieldErrors []FieldError
+	Body        []byte
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: status %d", e.Method, e.URL, e.StatusCode)
+
+	switch {
+	case e.Message != "":
+		fmt.Fprintf(&b, ": %s", e.Message)
+	case len(e.FieldErrors) == 0 && len(e.Body) > 0:
+		fmt.Fprintf(&b, ", body: %s", e.Body)
+	}
+
+	for _, fieldErr := range e.FieldErrors {
+		fmt.Fprintf(&b, "; %s: %s", fieldErr.Field, fieldErr.Message)
+	}
+
+	return b.String()
+}
+
+// Is allows errors.Is to match an APIError against the sentinel errors of
+// this package based on its status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrPreconditionFailed:
+		return e.StatusCode == http.StatusPreconditionFailed
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// patchUnsupported reports whether err shows that the API rejected a
+// PATCH request because it does not implement PATCH or merge patches.
+func patchUnsupported(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusUnsupportedMediaType:
+		return true
+	}
+	return false
+}
+
+// newAPIError builds an APIError from a failed response and its body.
+func newAPIError(req *http.Request, res *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Method:     req.Method,
+		URL:        req.URL.Redacted(),
+		Body:       body,
+	}
+	apiErr.Message, apiErr.FieldErrors = parseErrorBody(body)
+
+	return apiErr
+}
+
+// parseErrorBody extracts a message and field errors from the JSON error
+// shapes the API is known to produce:
+//
+//	{"error": "..."} or {"message": "..."}
+//	{"errors": {"email": "must be a valid email address"}}
+//	{"errors": [{"field": "email", "message": "..."}]}
+func parseErrorBody(body []byte) (string, []FieldError) {
+	var payload struct {
+		Error   string          `json:"error"`
+		Message string          `json:"message"`
+		Errors  json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", nil
+	}
+
+	message := payload.Message
+	if message == "" {
+		message = payload.Error
+	}
+
+	if len(payload.Errors) == 0 {
+		return message, nil
+	}
+
+	var list []FieldError
+	if err := json.Unmarshal(payload.Errors, &list); err == nil {
+		return message, list
+	}
+
+	var byField map[string]string
+	if err := json.Unmarshal(payload.Errors, &byField); err == nil {
+		fields := make([]string, 0, len(byField))
+		for field := range byField {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			list = append(list, FieldError{Field: field, Message: byField[field]})
+		}
+		return message, list
+	}
+
+	return message, nil
+}
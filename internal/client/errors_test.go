@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorClassification(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"invalid engineer","errors":{"email":"must be a valid email address"}}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+
+	_, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "nope"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, ErrValidation) || errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+		t.Errorf("unexpected classification for status %d", apiErr.StatusCode)
+	}
+	if apiErr.Message != "invalid engineer" {
+		t.Errorf("unexpected message %q", apiErr.Message)
+	}
+	if len(apiErr.FieldErrors) != 1 || apiErr.FieldErrors[0].Field != "email" {
+		t.Errorf("unexpected field errors %+v", apiErr.FieldErrors)
+	}
+}
+
+func TestParseErrorBodyFieldList(t *testing.T) {
+	message, fieldErrs := parseErrorBody([]byte(`{"error":"conflict","errors":[{"field":"name","message":"already taken"}]}`))
+	if message != "conflict" {
+		t.Errorf("unexpected message %q", message)
+	}
+	if len(fieldErrs) != 1 || fieldErrs[0] != (FieldError{Field: "name", Message: "already taken"}) {
+		t.Errorf("unexpected field errors %+v", fieldErrs)
+	}
+
+	if message, fieldErrs := parseErrorBody([]byte("not json")); message != "" || fieldErrs != nil {
+		t.Errorf("expected nothing parsed from plain text body, got %q %+v", message, fieldErrs)
+	}
+}
+
+func TestDeleteEngineerAlreadyDeleted(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+
+	if err := c.DeleteEngineer(context.Background(), "42", ""); err != nil {
+		t.Fatalf("expected deleting a missing engineer to succeed, got %s", err)
+	}
+}
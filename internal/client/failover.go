@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultEndpointCooldown is how long an endpoint that failed is skipped
+// in favour of the other configured endpoints.
+const DefaultEndpointCooldown = 30 * time.Second
+
+// WithEndpoints adds further API endpoints, such as other regions, to fail
+// over to when the endpoint passed to NewClient is unreachable or answers
+// with a server error. Endpoints are tried in order. Creates and other
+// requests that are not idempotent only move on while they have not
+// reached any endpoint.
+func WithEndpoints(endpoints ...string) Option {
+	return func(c *Client) {
+		c.extraEndpoints = append(c.extraEndpoints, endpoints...)
+	}
+}
+
+// WithEndpointCooldown sets how long a failed endpoint is skipped. It
+// defaults to DefaultEndpointCooldown.
+func WithEndpointCooldown(cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.cooldown = cooldown
+	}
+}
+
+// endpoint is one base URL of the API together with its health.
+type endpoint struct {
+	base *url.URL
+
+	mu             sync.Mutex
+	unhealthyUntil time.Time
+}
+
+func (e *endpoint) healthy(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return !now.Before(e.unhealthyUntil)
+}
+
+func (e *endpoint) setUnhealthyUntil(until time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.unhealthyUntil = until
+}
+
+// contains reports whether u points below this endpoint.
+func (e *endpoint) contains(u *url.URL) bool {
+	if u.Scheme != e.base.Scheme || u.Host != e.base.Host {
+		return false
+	}
+	prefix := e.base.EscapedPath()
+	path := u.EscapedPath()
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// ownsURL reports whether u points below one of the configured endpoints.
+func (c *Client) ownsURL(u *url.URL) bool {
+	for _, e := range c.endpoints {
+		if e.contains(u) {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointOrder returns the endpoints to try for one attempt: the healthy
+// ones in configured order, then those cooling down, so a request is still
+// tried everywhere when every endpoint has failed recently.
+func (c *Client) endpointOrder() []*endpoint {
+	now := time.Now()
+	order := make([]*endpoint, 0, len(c.endpoints))
+	var cooling []*endpoint
+	for _, e := range c.endpoints {
+		if e.healthy(now) {
+			order = append(order, e)
+		} else {
+			cooling = append(cooling, e)
+		}
+	}
+	return append(order, cooling...)
+}
+
+// rebase points req at target, keeping the path below the endpoint it
+// currently targets. URLs outside every endpoint are left alone.
+func (c *Client) rebase(req *http.Request, target *endpoint) {
+	for _, e := range c.endpoints {
+		if !e.contains(req.URL) {
+			continue
+		}
+
+		rebased := *req.URL
+		rebased.Scheme = target.base.Scheme
+		rebased.Host = target.base.Host
+		rebased.Path = target.base.Path + strings.TrimPrefix(req.URL.Path, e.base.Path)
+		rebased.RawPath = target.base.EscapedPath() + strings.TrimPrefix(req.URL.EscapedPath(), e.base.EscapedPath())
+		req.URL = &rebased
+		req.Host = ""
+		return
+	}
+}
+
+// doWithFailover performs one attempt of req, moving on to the next
+// endpoint when one cannot be reached or fails with a server error.
+func (c *Client) doWithFailover(ctx context.Context, req *http.Request, attempt int, decode decodeFunc) (*http.Response, []byte, error) {
+	var res *http.Response
+	var body []byte
+	var err error
+
+	d := deliveryOf(req)
+	order := c.endpointOrder()
+	if pinned := d.pinned(); pinned != nil {
+		order = []*endpoint{pinned}
+	}
+
+	for i, e := range order {
+		if i > 0 {
+			if !canFailover(req) {
+				break
+			}
+			if rewindErr := rewind(req); rewindErr != nil {
+				return nil, nil, rewindErr
+			}
+			tflog.SubsystemWarn(ctx, logSubsystem, "Failing over to next DevOps API endpoint", map[string]interface{}{
+				"http_endpoint": e.base.Redacted(),
+				"error":         err.Error(),
+			})
+		}
+
+		c.rebase(req, e)
+		res, body, err = c.do(ctx, req, attempt, decode)
+		if res != nil {
+			d.markWritten()
+		}
+		if d.wasWritten() && !idempotent(req) {
+			d.pin(e)
+		}
+
+		if !endpointFailed(req, res, err) {
+			if len(c.endpoints) > 1 {
+				e.setUnhealthyUntil(time.Time{})
+				tflog.SubsystemDebug(ctx, logSubsystem, "DevOps API endpoint served request", map[string]interface{}{
+					"http_method":   req.Method,
+					"http_endpoint": e.base.Redacted(),
+				})
+			}
+			break
+		}
+		e.setUnhealthyUntil(time.Now().Add(c.cooldown))
+	}
+
+	return res, body, err
+}
+
+// endpointFailed reports whether an attempt shows the endpoint itself is
+// in trouble, rather than the request being wrong. A 501 is not counted:
+// it means the API lacks an optional route, such as PATCH or /version.
+func endpointFailed(req *http.Request, res *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if res == nil {
+		return err != nil
+	}
+	switch res.StatusCode {
+	case http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// canFailover reports whether req may be sent to another endpoint after
+// the previous one failed. Requests that never reached a server can always
+// move on; others must be idempotent, since an Idempotency-Key only
+// protects against duplicates at the endpoint that saw it.
+func canFailover(req *http.Request) bool {
+	return !deliveryOf(req).wasWritten() || idempotent(req)
+}
+
+// rewind restores the body of req before it is sent again.
+func rewind(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
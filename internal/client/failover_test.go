@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFailoverOnConnectionError(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	var paths []string
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada@example.com"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer up.Close()
+
+	c, err := NewClient(down.URL+"/api", WithEndpoints(up.URL+"/eu/api"), WithRetryPolicy(RetryPolicy{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A refused connection never reached the server, so even a POST
+	// without an Idempotency-Key can move on to the next endpoint.
+	if _, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "ada@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.GetDevelopers(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"POST /eu/api/engineers", "GET /eu/api/dev"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("expected requests %v, got %v", expected, paths)
+	}
+	if c.endpoints[0].healthy(time.Now()) {
+		t.Error("expected the unreachable endpoint to be cooling down")
+	}
+}
+
+func TestFailoverOnServerError(t *testing.T) {
+	var primaryHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer primary.Close()
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer secondary.Close()
+
+	c, _ := NewClient(primary.URL, WithEndpoints(secondary.URL), WithRetryPolicy(RetryPolicy{}), WithEndpointCooldown(time.Hour))
+
+	for range 3 {
+		if _, err := c.engineers.fetchAll(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if hits := primaryHits.Load(); hits != 1 {
+		t.Errorf("expected the failing endpoint to be skipped during its cooldown, got %d requests", hits)
+	}
+}
+
+func TestNoFailoverForUnsafeRequests(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer primary.Close()
+
+	var secondaryHits atomic.Int32
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryHits.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer secondary.Close()
+
+	c, _ := NewClient(primary.URL, WithEndpoints(secondary.URL), WithRetryPolicy(RetryPolicy{}))
+
+	// The primary may have applied a POST without an Idempotency-Key
+	// before failing, so the request must not be repeated elsewhere.
+	req, _ := http.NewRequestWithContext(context.Background(), "POST", c.url("engineers"), strings.NewReader(`{"name":"Ada"}`))
+	if _, err := c.doRequest(req); err == nil {
+		t.Fatal("expected error")
+	}
+	if hits := secondaryHits.Load(); hits != 0 {
+		t.Errorf("expected no failover for a POST, got %d requests", hits)
+	}
+}
+
+func TestIdempotentCreateStaysOnItsEndpoint(t *testing.T) {
+	var primaryHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	var secondaryHits atomic.Int32
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryHits.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer secondary.Close()
+
+	c, _ := NewClient(primary.URL, WithEndpoints(secondary.URL), WithRetryPolicy(testRetryPolicy()))
+	c.setCapabilities(Capabilities{IdempotencyKeys: Supported})
+
+	// Only the primary has seen the Idempotency-Key, so retries may go
+	// there but never to another endpoint that would apply it again.
+	req, _ := http.NewRequestWithContext(context.Background(), "POST", c.url("engineers"), strings.NewReader(`{"name":"Ada"}`))
+	req, _ = c.withIdempotencyKey(req)
+	if _, err := c.doRequest(req); err == nil {
+		t.Fatal("expected error")
+	}
+	if hits := secondaryHits.Load(); hits != 0 {
+		t.Errorf("expected no failover for a create, got %d requests", hits)
+	}
+	if hits := primaryHits.Load(); hits != int32(testRetryPolicy().MaxRetries)+1 {
+		t.Errorf("expected every retry to go to the primary, got %d requests", hits)
+	}
+}
+
+func TestNotImplementedKeepsEndpointHealthy(t *testing.T) {
+	var secondaryHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The primary has no single-item route, which is not an outage.
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryHits.Add(1)
+	}))
+	defer secondary.Close()
+
+	c, _ := NewClient(primary.URL, WithEndpoints(secondary.URL), WithRetryPolicy(RetryPolicy{}), WithCircuitBreaker(1, time.Hour))
+
+	for range 2 {
+		var apiErr *APIError
+		if _, err := c.engineers.fetchOne(context.Background(), "1"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+			t.Fatalf("expected the 501 without opening the breaker, got %v", err)
+		}
+	}
+	if hits := secondaryHits.Load(); hits != 0 {
+		t.Errorf("expected no failover on 501, got %d requests", hits)
+	}
+	if !c.endpoints[0].healthy(time.Now()) {
+		t.Error("expected the primary to stay healthy")
+	}
+}
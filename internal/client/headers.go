@@ -0,0 +1,38 @@
+package client
+
+import "net/http"
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeaders adds static headers, such as tenant IDs or routing hints, to
+// every request. Headers the client sets itself for a request, like
+// Content-Type or If-Match, take precedence.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		c.headers = make(http.Header, len(headers))
+		for key, value := range headers {
+			c.headers.Set(key, value)
+		}
+	}
+}
+
+// setHeaders attaches the User-Agent, static headers and the accepted
+// response encodings to req.
+func (c *Client) setHeaders(req *http.Request) {
+	for key, values := range c.headers {
+		if _, ok := req.Header[key]; !ok {
+			req.Header[key] = values
+		}
+	}
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+}
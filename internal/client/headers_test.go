@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserAgentAndStaticHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada@example.com"}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL,
+		WithUserAgent("terraform-provider-devops-bootcamp/1.2.3 Terraform/1.9.0"),
+		WithHeaders(map[string]string{
+			"x-tenant-id":  "acme",
+			"Content-Type": "text/plain",
+		}),
+		WithToken("s3cr3t"),
+	)
+
+	if _, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "ada@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if ua := got.Get("User-Agent"); ua != "terraform-provider-devops-bootcamp/1.2.3 Terraform/1.9.0" {
+		t.Errorf("unexpected User-Agent %q", ua)
+	}
+	if tenant := got.Get("X-Tenant-Id"); tenant != "acme" {
+		t.Errorf("expected static header, got %q", tenant)
+	}
+	if ct := got.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("static header overrode request Content-Type: %q", ct)
+	}
+	if auth := got.Get("Authorization"); auth != "Bearer s3cr3t" {
+		t.Errorf("unexpected Authorization %q", auth)
+	}
+}
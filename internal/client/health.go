@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ErrIncompatibleAPI is returned by Probe when the endpoint answers but
+// does not look like a DevOps API.
+var ErrIncompatibleAPI = errors.New("endpoint is not a compatible DevOps API")
+
+// Support says whether the API offers an optional feature.
+type Support int32
+
+const (
+	// SupportUnknown means the server has not said either way. The client
+	// detects the feature on first use.
+	SupportUnknown Support = iota
+	// Supported means the server offers the feature.
+	Supported
+	// Unsupported means the server lacks the feature.
+	Unsupported
+)
+
+func (s Support) String() string {
+	switch s {
+	case Supported:
+		return "supported"
+	case Unsupported:
+		return "unsupported"
+	default:
+		return "unknown"
+	}
+}
+
+// Capabilities describes the API server as discovered by Probe.
+type Capabilities struct {
+	// APIVersion is the version the server reported, if any.
+	APIVersion string
+
+	// Patch is whether the server accepts JSON merge patches.
+	Patch Support
+
+	// SingleItemGet is whether the server serves individual items, such
+	// as /engineers/{id}.
+	SingleItemGet Support
+
+	// IdempotencyKeys is whether the server applies a create at most once
+	// per Idempotency-Key. Only then are creates retried or sent again
+	// after their outcome is unknown.
+	IdempotencyKeys Support
+}
+
+// versionResponse is the body of GET /version. Capabilities may be given
+// as a list of names or as a map of names to booleans.
+type versionResponse struct {
+	Version      string          `json:"version"`
+	APIVersion   string          `json:"api_version"`
+	Capabilities json.RawMessage `json:"capabilities"`
+}
+
+// Capability names understood in a /version response.
+const (
+	capabilityPatch           = "patch"
+	capabilitySingleItemGet   = "single_item_get"
+	capabilityIdempotencyKeys = "idempotency_keys"
+)
+
+// Probe checks that the API is reachable and discovers its capabilities.
+// It asks /version first, then /health, and finally falls back to reading
+// one page of the engineers collection. Discovered capabilities are
+// remembered by the client, so later requests skip feature detection. A
+// /version or /health answer that is not JSON, such as a plain version
+// string or the page of a web server answering every path, proves
+// nothing, so the next check is tried as if the route were missing.
+func (c *Client) Probe(ctx context.Context) (Capabilities, error) {
+	var capabilities Capabilities
+
+	body, err := c.probeGet(ctx, c.url("version"))
+	switch {
+	case err == nil:
+		if err := parseVersion(body, &capabilities); err != nil {
+			tflog.SubsystemDebug(c.logContext(ctx), logSubsystem, "Ignoring unreadable /version response", map[string]interface{}{
+				"error": err.Error(),
+			})
+			capabilities = Capabilities{}
+			break
+		}
+		c.setCapabilities(capabilities)
+		return capabilities, nil
+	case !probeRouteMissing(err):
+		return capabilities, err
+	}
+
+	body, err = c.probeGet(ctx, c.url("health"))
+	switch {
+	case err == nil:
+		if json.Valid(body) {
+			return capabilities, nil
+		}
+		tflog.SubsystemDebug(c.logContext(ctx), logSubsystem, "Ignoring /health response that is not JSON")
+	case !probeRouteMissing(err):
+		return capabilities, err
+	}
+
+	collectionURL, err := url.Parse(c.url("engineers"))
+	if err != nil {
+		return capabilities, err
+	}
+	query := collectionURL.Query()
+	query.Set("limit", "1")
+	collectionURL.RawQuery = query.Encode()
+
+	if _, _, err := fetchPage[Engineer](ctx, c, collectionURL); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, ErrNotFound) {
+			return capabilities, fmt.Errorf("%w: GET /engineers: %s", ErrIncompatibleAPI, err)
+		}
+		return capabilities, err
+	}
+
+	return capabilities, nil
+}
+
+// Capabilities returns what the client knows about the server, whether
+// learned by Probe or detected from earlier requests. Probe results apply
+// to every collection, but route support detected from requests is kept
+// per collection: this reports the engineers collection, which every API
+// version serves, and Collection.Capabilities reports the others.
+func (c *Client) Capabilities() Capabilities {
+	return c.engineers.Capabilities()
+}
+
+// setCapabilities records discovered capabilities. Unknown ones are left
+// to detection on first use.
+func (c *Client) setCapabilities(capabilities Capabilities) {
+	c.apiVersion = capabilities.APIVersion
+	if capabilities.IdempotencyKeys != SupportUnknown {
+		c.idempotencyKeys.set(capabilities.IdempotencyKeys)
+	}
+	c.engineers.setSupport(capabilities.SingleItemGet, capabilities.Patch)
+	c.developers.setSupport(capabilities.SingleItemGet, capabilities.Patch)
+	c.ops.setSupport(capabilities.SingleItemGet, capabilities.Patch)
+	c.devOps.setSupport(capabilities.SingleItemGet, capabilities.Patch)
+}
+
+func (c *Client) probeGet(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.doRequest(req)
+}
+
+// probeRouteMissing reports whether err means the probed route does not
+// exist, so the next probe should be tried.
+func probeRouteMissing(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+func parseVersion(body []byte, capabilities *Capabilities) error {
+	var version versionResponse
+	if err := json.Unmarshal(body, &version); err != nil {
+		return err
+	}
+
+	capabilities.APIVersion = version.APIVersion
+	if capabilities.APIVersion == "" {
+		capabilities.APIVersion = version.Version
+	}
+
+	if len(version.Capabilities) == 0 || string(version.Capabilities) == "null" {
+		return nil
+	}
+
+	// A list names the supported capabilities, so anything missing from
+	// it is unsupported. A map may leave capabilities unknown.
+	var names []string
+	if err := json.Unmarshal(version.Capabilities, &names); err == nil {
+		capabilities.Patch = Unsupported
+		capabilities.SingleItemGet = Unsupported
+		capabilities.IdempotencyKeys = Unsupported
+		for _, name := range names {
+			switch name {
+			case capabilityPatch:
+				capabilities.Patch = Supported
+			case capabilitySingleItemGet:
+				capabilities.SingleItemGet = Supported
+			case capabilityIdempotencyKeys:
+				capabilities.IdempotencyKeys = Supported
+			}
+		}
+		return nil
+	}
+
+	var flags map[string]bool
+	if err := json.Unmarshal(version.Capabilities, &flags); err != nil {
+		return fmt.Errorf("capabilities must be a list or an object: %w", err)
+	}
+	for name, supported := range flags {
+		support := Unsupported
+		if supported {
+			support = Supported
+		}
+		switch name {
+		case capabilityPatch:
+			capabilities.Patch = support
+		case capabilitySingleItemGet:
+			capabilities.SingleItemGet = support
+		case capabilityIdempotencyKeys:
+			capabilities.IdempotencyKeys = support
+		}
+	}
+
+	return nil
+}
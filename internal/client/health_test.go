@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProbe(t *testing.T) {
+	testCases := map[string]struct {
+		routes        map[string]string
+		expected      Capabilities
+		incompatible  bool
+		expectedError bool
+	}{
+		"version-list": {
+			routes: map[string]string{
+				"/version": `{"api_version":"1.4","capabilities":["patch"]}`,
+			},
+			expected: Capabilities{APIVersion: "1.4", Patch: Supported, SingleItemGet: Unsupported, IdempotencyKeys: Unsupported},
+		},
+		"version-idempotency-keys": {
+			routes: map[string]string{
+				"/version": `{"api_version":"1.5","capabilities":["idempotency_keys"]}`,
+			},
+			expected: Capabilities{APIVersion: "1.5", Patch: Unsupported, SingleItemGet: Unsupported, IdempotencyKeys: Supported},
+		},
+		"version-map": {
+			routes: map[string]string{
+				"/version": `{"version":"2.0.1","capabilities":{"single_item_get":true}}`,
+			},
+			expected: Capabilities{APIVersion: "2.0.1", SingleItemGet: Supported},
+		},
+		"version-not-json": {
+			routes: map[string]string{
+				"/version":   `1.4.2`,
+				"/engineers": `[]`,
+			},
+		},
+		"html-catch-all": {
+			routes: map[string]string{
+				"/version":   `<html>Welcome</html>`,
+				"/health":    `<html>Welcome</html>`,
+				"/engineers": `<html>Welcome</html>`,
+			},
+			incompatible: true,
+		},
+		"health": {
+			routes: map[string]string{
+				"/health": `{"status":"ok"}`,
+			},
+		},
+		"collection-fallback": {
+			routes: map[string]string{
+				"/engineers": `[]`,
+			},
+		},
+		"not-json": {
+			routes: map[string]string{
+				"/engineers": `<html>Welcome</html>`,
+			},
+			incompatible: true,
+		},
+		"nothing": {
+			incompatible: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, ok := testCase.routes[r.URL.Path]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}))
+			capabilities, err := c.Probe(context.Background())
+
+			if testCase.incompatible {
+				if !errors.Is(err, ErrIncompatibleAPI) {
+					t.Fatalf("expected ErrIncompatibleAPI, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if capabilities != testCase.expected {
+				t.Errorf("expected %+v, got %+v", testCase.expected, capabilities)
+			}
+			if c.Capabilities() != testCase.expected {
+				t.Errorf("client did not record %+v, has %+v", testCase.expected, c.Capabilities())
+			}
+			if c.Ops().Capabilities() != testCase.expected || c.DevOps().Capabilities() != testCase.expected {
+				t.Errorf("collections did not record %+v", testCase.expected)
+			}
+		})
+	}
+}
+
+func TestProbeUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}))
+	_, err := c.Probe(context.Background())
+	if err == nil || errors.Is(err, ErrIncompatibleAPI) {
+		t.Errorf("expected connection error, got %v", err)
+	}
+}
+
+func TestProbeSkipsUnsupportedRoutes(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/version":
+			_, _ = w.Write([]byte(`{"api_version":"1","capabilities":[]}`))
+		case "/engineers":
+			_, _ = w.Write([]byte(`[{"id":"1","name":"Ada","email":"ada@example.com"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+	if _, err := c.Probe(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.GetEngineer(context.Background(), "1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"/version", "/engineers"}
+	if len(paths) != len(expected) || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Errorf("expected requests %v, got %v", expected, paths)
+	}
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// idempotencyKeyHeader lets the API recognise repeated deliveries of the
+// same create. The client only relies on that when the API advertises the
+// idempotency_keys capability.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// newIdempotencyKey returns a random UUID to identify one create call. All
+// retries of that call send the same key.
+func newIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// withIdempotencyKey returns req with a new Idempotency-Key. When the API
+// honours the key, the request is marked so it may be sent again; the mark
+// comes from the client, never from a header a caller could set.
+func (c *Client) withIdempotencyKey(req *http.Request) (*http.Request, *delivery) {
+	req.Header.Set(idempotencyKeyHeader, newIdempotencyKey())
+	req, d := trackDelivery(req)
+	if c.idempotencyKeys.get() == Supported {
+		d.markKeyed()
+	}
+	return req, d
+}
+
+// outcomeUnknown reports whether a failed request may still have been
+// applied by the API: the response was lost, or a server error arrived
+// after the API may already have committed the change. Client errors and
+// requests that were never sent, such as failed dials or requests held
+// back by the circuit breaker, are definite failures. So are requests
+// whose context ended, since there is no time left to recover them.
+func outcomeUnknown(err error, sent bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return sent
+}
+
+// recoverCreate finds the item a create with an unknown outcome may have
+// produced. On APIs that honour Idempotency-Key it first sends req again:
+// the API answers with the item if the first request was applied and
+// creates it once if not. Failing that, the collection is
+// searched by natural key, ignoring the IDs in existing, which were known
+// before the create was sent. Without that snapshot a new item cannot be
+// told from an older one with the same content, so nothing is searched,
+// unless the natural key is unique.
+// It returns nil when nothing is found, in which case the caller reports
+// the original error.
+func (col *Collection[T, P]) recoverCreate(ctx context.Context, req *http.Request, item T, existing map[string]bool, createErr error) *T {
+	ctx = col.client.logContext(ctx)
+	kind := col.def.kind
+	tflog.SubsystemWarn(ctx, logSubsystem, "Outcome of the "+kind+" create unknown", map[string]interface{}{
+		"error": createErr.Error(),
+	})
+
+	if deliveryOf(req).wasKeyed() {
+		created, err := col.resendCreate(req)
+		if err == nil {
+			tflog.SubsystemInfo(ctx, logSubsystem, "Create of the "+kind+" confirmed by sending it again")
+			return created
+		}
+		tflog.SubsystemWarn(ctx, logSubsystem, "Sending the "+kind+" create again failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	if col.def.sameItem == nil {
+		return nil
+	}
+	if existing == nil && !col.def.uniqueKey {
+		tflog.SubsystemWarn(ctx, logSubsystem, "Cannot search for the "+kind+", as a new one cannot be told from existing ones")
+		return nil
+	}
+
+	tflog.SubsystemWarn(ctx, logSubsystem, "Searching for the "+kind)
+
+	col.cache.invalidate()
+	items, err := col.cache.list(ctx, col.fetchAll)
+	if err != nil {
+		tflog.SubsystemWarn(ctx, logSubsystem, "Searching for the "+kind+" failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	matches := col.def.sameItem(item)
+	var found []T
+	for _, item := range items {
+		if !existing[col.def.id(item)] && matches(item) {
+			found = append(found, item)
+		}
+	}
+
+	// Several new matches mean concurrent creates of the same content,
+	// which cannot be told apart, so only a unique match is adopted.
+	if len(found) != 1 {
+		tflog.SubsystemWarn(ctx, logSubsystem, "No unique "+kind+" found after failed create", map[string]interface{}{
+			"matches": len(found),
+		})
+		return nil
+	}
+
+	tflog.SubsystemInfo(ctx, logSubsystem, "Found "+kind+" created despite the error")
+	return &found[0]
+}
+
+// snapshot returns the IDs of the items that exist before a create, so that
+// none of them is mistaken for its result when the outcome is unknown. The
+// cached collection is used when there is one. Collections whose natural
+// key is not unique are listed first otherwise, as when the provider reads
+// items one by one; those with a unique key skip that download for every
+// create. It returns nil when nothing is known, including for collections
+// without a natural key to search by and when the listing fails.
+func (col *Collection[T, P]) snapshot(ctx context.Context) map[string]bool {
+	if col.def.sameItem == nil {
+		return nil
+	}
+	if existing, loaded := col.cache.ids(); loaded || col.def.uniqueKey {
+		return existing
+	}
+
+	items, err := col.cache.list(ctx, col.fetchAll)
+	if err != nil {
+		tflog.SubsystemWarn(col.client.logContext(ctx), logSubsystem, "Listing "+col.def.kind+" items before create failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	existing := make(map[string]bool, len(items))
+	for _, item := range items {
+		existing[col.def.id(item)] = true
+	}
+
+	return existing
+}
+
+// resendCreate sends a create again with its original Idempotency-Key.
+func (col *Collection[T, P]) resendCreate(req *http.Request) (*T, error) {
+	if err := rewind(req); err != nil {
+		return nil, err
+	}
+
+	res, body, err := col.client.doRequestWithResponse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return col.decode(res, body)
+}
+
+// sameEngineer reports whether existing is the engineer a create of want
+// would have produced, matching on the email address as natural key.
+func sameEngineer(want Engineer) func(Engineer) bool {
+	return func(existing Engineer) bool {
+		return strings.EqualFold(existing.Email, want.Email)
+	}
+}
+
+// sameDeveloper reports whether existing is the developer team a create of
+// want would have produced, matching on the team name.
+func sameDeveloper(want Developer) func(Developer) bool {
+	return func(existing Developer) bool {
+		return existing.Name == want.Name
+	}
+}
+
+// sameOps reports whether existing is the ops team a create of want would
+// have produced, matching on the team name.
+func sameOps(want Ops) func(Ops) bool {
+	return func(existing Ops) bool {
+		return existing.Name == want.Name
+	}
+}
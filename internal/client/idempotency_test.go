@@ -0,0 +1,309 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCreateSendsStableIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada@example.com"}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	c.setCapabilities(Capabilities{IdempotencyKeys: Supported})
+	if _, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "ada@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(keys) != 3 {
+		t.Fatalf("expected the create to be retried, got %d requests", len(keys))
+	}
+	if keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Errorf("expected one key for all attempts, got %q", keys)
+	}
+
+	if _, err := c.CreateEngineer(context.Background(), Engineer{Name: "Grace", Email: "grace@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if keys[3] == keys[0] {
+		t.Error("expected a new key for a new create")
+	}
+}
+
+func TestCreateNotRetriedWithoutIdempotencyKeys(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts.Add(1)
+		}
+		// The API may have created the engineer, but it ignores
+		// Idempotency-Key, so sending the create again could duplicate it.
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	if _, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "ada@example.com"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := posts.Load(); got != 1 {
+		t.Errorf("expected the create to be sent once, got %d", got)
+	}
+}
+
+// lostResponseServer answers every create with status, as if the response
+// was lost on the way back. When apply is set the create still takes
+// effect, once per Idempotency-Key.
+func lostResponseServer(t *testing.T, collection string, existing []interface{}, status int, apply bool) *httptest.Server {
+	t.Helper()
+
+	items := existing
+	keys := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			var item map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&item)
+			item["id"] = "new"
+			if key := r.Header.Get(idempotencyKeyHeader); apply && !keys[key] {
+				keys[key] = true
+				items = append(items, item)
+			}
+			w.WriteHeader(status)
+		case http.MethodGet:
+			if r.URL.Path != "/"+collection {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(items)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestCreateEngineerRecoversLostResponse(t *testing.T) {
+	server := lostResponseServer(t, "engineers", []interface{}{
+		map[string]string{"id": "1", "name": "Grace", "email": "grace@example.com"},
+	}, http.StatusGatewayTimeout, true)
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}))
+	if _, err := c.GetEngineers(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	engineer, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "Ada@Example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if engineer.ID != "new" {
+		t.Errorf("expected the created engineer, got %+v", engineer)
+	}
+}
+
+func TestCreateRecoversByResending(t *testing.T) {
+	var posts atomic.Int32
+	created := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		posts.Add(1)
+
+		// The API replays the response of a create it already applied.
+		key := r.Header.Get(idempotencyKeyHeader)
+		if body, ok := created[key]; ok {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		created[key] = `{"id":"new","name":"Ada","email":"ada@example.com"}`
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}))
+	c.setCapabilities(Capabilities{IdempotencyKeys: Supported})
+	engineer, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if engineer.ID != "new" || len(created) != 1 || posts.Load() != 2 {
+		t.Errorf("expected the create to be replayed once, got %+v after %d requests", engineer, posts.Load())
+	}
+}
+
+func TestCreateEngineerSearchesWithoutCache(t *testing.T) {
+	server := lostResponseServer(t, "engineers", []interface{}{
+		map[string]string{"id": "1", "name": "Grace", "email": "grace@example.com"},
+	}, http.StatusGatewayTimeout, true)
+
+	// Nothing is cached, as when the provider reads engineers one by one,
+	// and the API does not advertise idempotency keys.
+	c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}))
+	engineer, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada Lovelace", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if engineer.ID != "new" {
+		t.Errorf("expected the created engineer, got %+v", engineer)
+	}
+}
+
+func TestCreateEngineerDoesNotListFirst(t *testing.T) {
+	var lists atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			lists.Add(1)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada@example.com"}`))
+	}))
+	defer server.Close()
+
+	// Emails are unique, so a lost response can be recovered without
+	// knowing which engineers existed before.
+	c, _ := NewClient(server.URL)
+	if _, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "ada@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := lists.Load(); got != 0 {
+		t.Errorf("expected no list before the create, got %d", got)
+	}
+}
+
+func TestCreateDeveloperIgnoresExistingTeams(t *testing.T) {
+	server := lostResponseServer(t, "dev", []interface{}{
+		map[string]interface{}{"id": "1", "name": "Frontend", "engineers": []interface{}{}},
+	}, http.StatusGatewayTimeout, true)
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}))
+	if _, err := c.GetDevelopers(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The older team has the same name, but it was known before the
+	// create, so only the new one matches.
+	developer, err := c.CreateDeveloper(context.Background(), Developer{Name: "Frontend"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if developer.ID != "new" {
+		t.Errorf("expected the created team, got %+v", developer)
+	}
+}
+
+func TestCreateDoesNotAdoptExistingItem(t *testing.T) {
+	tests := map[string]struct {
+		preload bool
+	}{
+		"known before the create":   {preload: true},
+		"nothing known before then": {preload: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Team names are not unique, so an older team of the same
+			// name must not be taken for the result of the create.
+			server := lostResponseServer(t, "dev", []interface{}{
+				map[string]interface{}{"id": "1", "name": "Frontend", "engineers": []interface{}{}},
+			}, http.StatusInternalServerError, false)
+
+			c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}))
+			if tt.preload {
+				if _, err := c.GetDevelopers(context.Background()); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			_, err := c.CreateDeveloper(context.Background(), Developer{Name: "Frontend"})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+				t.Fatalf("expected the server error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateDoesNotSearchAfterDefiniteFailure(t *testing.T) {
+	lists := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			lists++
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"errors":{"email":"must be a valid email address"}}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+	if _, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "nope"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+	if lists != 0 {
+		t.Errorf("expected no search after a rejected create, got %d", lists)
+	}
+}
+
+func TestCreateDoesNotSearchWhenNeverSent(t *testing.T) {
+	lists := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lists++
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}))
+	c.HTTPClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	if _, err := c.GetEngineers(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.CreateEngineer(context.Background(), Engineer{Name: "Ada", Email: "ada@example.com"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if lists != 1 {
+		t.Errorf("expected no search after a create that was never sent, got %d lists", lists-1)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
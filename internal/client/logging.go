@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// logSubsystem is the tflog subsystem used for HTTP traffic. Its level can
+// be set separately with TF_LOG_PROVIDER_DEVOPS_API.
+const logSubsystem = "devops_api"
+
+// emailPattern matches email addresses so engineer PII never reaches the
+// logs.
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// sensitiveHeaders are replaced wholesale when headers are logged.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// logContext returns ctx with the client's log subsystem and its masking
+// rules attached.
+func (c *Client) logContext(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, logSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_DEVOPS_API"))
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, logSubsystem, emailPattern)
+	ctx = tflog.SubsystemMaskMessageRegexes(ctx, logSubsystem, emailPattern)
+
+	var secrets []string
+	for _, secret := range []string{c.token, c.password} {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	if len(secrets) > 0 {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, logSubsystem, secrets...)
+		ctx = tflog.SubsystemMaskMessageStrings(ctx, logSubsystem, secrets...)
+	}
+
+	return ctx
+}
+
+// logRequest logs an outgoing attempt of req.
+func logRequest(ctx context.Context, req *http.Request, attempt int) {
+	tflog.SubsystemDebug(ctx, logSubsystem, "Sending HTTP request", map[string]interface{}{
+		"http_method":  req.Method,
+		"http_url":     req.URL.Redacted(),
+		"http_attempt": attempt + 1,
+	})
+
+	fields := map[string]interface{}{
+		"http_method":      req.Method,
+		"http_url":         req.URL.Redacted(),
+		"http_req_headers": redactHeaders(req.Header),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			fields["http_req_body"] = string(data)
+		}
+	}
+	tflog.SubsystemTrace(ctx, logSubsystem, "HTTP request details", fields)
+}
+
+// logResponse logs the outcome of an attempt of req.
+func logResponse(ctx context.Context, req *http.Request, res *http.Response, body []byte, err error, attempt int, latency time.Duration) {
+	fields := map[string]interface{}{
+		"http_method":     req.Method,
+		"http_url":        req.URL.Redacted(),
+		"http_attempt":    attempt + 1,
+		"http_latency_ms": latency.Milliseconds(),
+	}
+
+	if res == nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, logSubsystem, "HTTP request failed", fields)
+		return
+	}
+
+	fields["http_status"] = res.StatusCode
+	tflog.SubsystemDebug(ctx, logSubsystem, "Received HTTP response", fields)
+
+	details := map[string]interface{}{
+		"http_status":       res.StatusCode,
+		"http_res_headers":  redactHeaders(res.Header),
+		"http_content_type": res.Header.Get("Content-Type"),
+	}
+	// Streamed bodies are decoded as they arrive and never held in full.
+	if body != nil {
+		details["http_res_body"] = string(body)
+	}
+	tflog.SubsystemTrace(ctx, logSubsystem, "HTTP response details", details)
+}
+
+// redactHeaders flattens headers into a single string with credentials
+// replaced.
+func redactHeaders(header http.Header) string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.Join(header.Values(key), ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			value = "***"
+		}
+		lines = append(lines, key+": "+value)
+	}
+
+	return strings.Join(lines, "\n")
+}
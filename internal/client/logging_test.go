@@ -0,0 +1,39 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestDoRequestLogsWithRedaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada.lovelace@example.com"}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	c, _ := NewClient(server.URL, WithToken("super-secret-token"))
+	if _, err := c.CreateEngineer(ctx, Engineer{Name: "Ada", Email: "ada.lovelace@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	logs := output.String()
+	for _, expected := range []string{"Sending HTTP request", "Received HTTP response", `"http_status":200`, "http_latency_ms", "http_res_body"} {
+		if !strings.Contains(logs, expected) {
+			t.Errorf("expected logs to contain %q:\n%s", expected, logs)
+		}
+	}
+	for _, secret := range []string{"ada.lovelace@example.com", "super-secret-token"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("expected %q to be masked:\n%s", secret, logs)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// routeSupport records whether the API serves an optional route. Older
+// API versions have no single-item GET, so support is detected on first
+// use and remembered for the rest of the run.
+type routeSupport struct {
+	state atomic.Int32
+}
+
+func (rs *routeSupport) get() Support {
+	return Support(rs.state.Load())
+}
+
+func (rs *routeSupport) set(state Support) {
+	rs.state.Store(int32(state))
+}
+
+// lookup finds a single item by ID. The direct GET route is tried first and
+// the client falls back to scanning the collection when the server lacks
+// that route. Until the route is known to work, an already cached
+// collection answers without any request. It returns nil without error
+// when the item does not exist.
+func lookup[T any](
+	ctx context.Context,
+	support *routeSupport,
+	cache *collectionCache[T],
+	id string,
+	fetchOne func(context.Context, string) (*T, error),
+	fetchAll func(context.Context) ([]T, error),
+) (*T, error) {
+	// Prefer the direct route once the server is known to serve it: the
+	// result is fresh and carries the ETag used for conditional writes.
+	state := support.get()
+	if state != Supported {
+		if item, loaded := cache.cached(id); loaded {
+			return item, nil
+		}
+	}
+
+	if state != Unsupported {
+		item, err := fetchOne(ctx, id)
+		if err == nil {
+			support.set(Supported)
+			return item, nil
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			return nil, err
+		}
+
+		switch apiErr.StatusCode {
+		case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			support.set(Unsupported)
+		case http.StatusNotFound:
+			// A 404 from a server known to serve the route means the
+			// item is gone. Otherwise the route itself may be missing,
+			// which only the collection can tell apart.
+			if state == Supported {
+				return nil, nil
+			}
+		default:
+			return nil, err
+		}
+	}
+
+	item, err := cache.get(ctx, id, fetchAll)
+	if err != nil {
+		return nil, err
+	}
+
+	if item != nil && state == SupportUnknown {
+		support.set(Unsupported)
+	}
+
+	return item, nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetEngineerDirectRoute(t *testing.T) {
+	var lists atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/engineers":
+			lists.Add(1)
+			_, _ = w.Write([]byte(`[]`))
+		case "/engineers/1":
+			_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada@example.com"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"engineer not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+	ctx := context.Background()
+
+	if engineer, err := c.GetEngineer(ctx, "1"); err != nil || engineer.Name != "Ada" {
+		t.Fatalf("expected engineer, got %+v, %v", engineer, err)
+	}
+	if _, err := c.GetEngineer(ctx, "2"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if got := lists.Load(); got != 0 {
+		t.Fatalf("expected no collection fetches, got %d", got)
+	}
+	if got := c.engineers.byID.get(); got != Supported {
+		t.Fatalf("expected direct route to be marked supported, got %d", got)
+	}
+}
+
+func TestGetDeveloperFallsBackWithoutDirectRoute(t *testing.T) {
+	var direct atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dev" {
+			direct.Add(1)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":"7","name":"Platform","engineers":[]}]`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+	ctx := context.Background()
+
+	if developer, err := c.GetDeveloper(ctx, "7"); err != nil || developer.Name != "Platform" {
+		t.Fatalf("expected developer, got %+v, %v", developer, err)
+	}
+	c.developers.invalidate()
+	if _, err := c.GetDeveloper(ctx, "7"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := direct.Load(); got != 1 {
+		t.Fatalf("expected the direct route to be tried once, got %d", got)
+	}
+}
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metrics collects request counts, retries and latencies of the clients it
+// is passed to, keyed by HTTP method and route. Routes name collections
+// and items rather than concrete IDs, such as "/engineers/{id}", to keep
+// the number of series small.
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	retries  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetrics returns an empty set of client metrics.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "devops_api_requests_total",
+			Help: "Requests sent to the DevOps API, including retries, by response status code or \"error\" for transport failures.",
+		}, []string{"method", "route", "code"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "devops_api_retries_total",
+			Help: "Requests to the DevOps API that were sent again after a transient failure.",
+		}, []string{"method", "route"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "devops_api_request_duration_seconds",
+			Help:    "Time until the DevOps API answered a request with its response headers.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+	m.registry.MustRegister(m.requests, m.retries, m.duration)
+
+	return m
+}
+
+// WithMetrics records the client's requests in m. Several clients may
+// share one Metrics.
+func WithMetrics(m *Metrics) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// WriteFile adds the metrics to those already in the file at path, in the
+// Prometheus text exposition format. Terraform runs a provider process for
+// each walk of the graph, so one command such as terraform apply is spread
+// over several processes, and each adds its counts to the file. The file
+// is replaced atomically so a collector such as the node exporter's
+// textfile collector never reads a partial file.
+func (m *Metrics) WriteFile(path string) error {
+	families, err := m.registry.Gather()
+	if err != nil {
+		return err
+	}
+
+	previous, err := readMetricsFile(path)
+	if err != nil {
+		return err
+	}
+	families = mergeFamilies(families, previous)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// Collectors often run as another user.
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(tmp, family); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// readMetricsFile parses the metrics written to path by earlier provider
+// processes. A missing file holds no metrics.
+func readMetricsFile(path string) (map[string]*dto.MetricFamily, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading metrics from %s: %w", path, err)
+	}
+	return families, nil
+}
+
+// mergeFamilies adds the counters and histograms in previous to families,
+// matching series by name and labels. Series only found in previous are
+// kept as they are.
+func mergeFamilies(families []*dto.MetricFamily, previous map[string]*dto.MetricFamily) []*dto.MetricFamily {
+	for _, family := range families {
+		old, ok := previous[family.GetName()]
+		if !ok || old.GetType() != family.GetType() {
+			continue
+		}
+		delete(previous, family.GetName())
+
+		for _, oldMetric := range old.Metric {
+			metric := findMetric(family.Metric, labelKey(oldMetric))
+			if metric == nil {
+				family.Metric = append(family.Metric, oldMetric)
+				continue
+			}
+			addMetric(metric, oldMetric)
+		}
+	}
+
+	for _, old := range previous {
+		families = append(families, old)
+	}
+	slices.SortFunc(families, func(a, b *dto.MetricFamily) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+
+	return families
+}
+
+// labelKey identifies a series within its family.
+func labelKey(metric *dto.Metric) string {
+	pairs := make([]string, 0, len(metric.Label))
+	for _, label := range metric.Label {
+		pairs = append(pairs, label.GetName()+"="+strconv.Quote(label.GetValue()))
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func findMetric(metrics []*dto.Metric, key string) *dto.Metric {
+	for _, metric := range metrics {
+		if labelKey(metric) == key {
+			return metric
+		}
+	}
+	return nil
+}
+
+// addMetric adds the values of old to metric.
+func addMetric(metric, old *dto.Metric) {
+	if metric.Counter != nil && old.Counter != nil {
+		metric.Counter.Value = proto.Float64(metric.Counter.GetValue() + old.Counter.GetValue())
+	}
+
+	if metric.Histogram != nil && old.Histogram != nil {
+		h := metric.Histogram
+		h.SampleCount = proto.Uint64(h.GetSampleCount() + old.Histogram.GetSampleCount())
+		h.SampleSum = proto.Float64(h.GetSampleSum() + old.Histogram.GetSampleSum())
+		for _, bucket := range h.Bucket {
+			for _, oldBucket := range old.Histogram.Bucket {
+				if oldBucket.GetUpperBound() == bucket.GetUpperBound() {
+					bucket.CumulativeCount = proto.Uint64(bucket.GetCumulativeCount() + oldBucket.GetCumulativeCount())
+				}
+			}
+		}
+	}
+}
+
+// observe records one attempt of req. A nil Metrics records nothing.
+func (m *Metrics) observe(req *http.Request, route string, res *http.Response, err error, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	m.requests.WithLabelValues(req.Method, route, code).Inc()
+	m.duration.WithLabelValues(req.Method, route).Observe(elapsed.Seconds())
+}
+
+// retried records that req is sent again.
+func (m *Metrics) retried(req *http.Request, route string) {
+	if m == nil {
+		return
+	}
+
+	m.retries.WithLabelValues(req.Method, route).Inc()
+}
+
+// route returns the metrics label for u: its collection path below the
+// endpoint it targets, with any item ID replaced by a placeholder.
+func (c *Client) route(u *url.URL) string {
+	for _, e := range c.endpoints {
+		if !e.contains(u) {
+			continue
+		}
+
+		rest := strings.Trim(strings.TrimPrefix(u.Path, e.base.Path), "/")
+		collection, item, _ := strings.Cut(rest, "/")
+		if item != "" {
+			return "/" + collection + "/{id}"
+		}
+		return "/" + collection
+	}
+
+	return "other"
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/engineers" && calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/api/engineers/7" {
+			_, _ = w.Write([]byte(`{"id":"7","name":"Ada","email":"ada@example.com"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	metrics := NewMetrics()
+	c, _ := NewClient(server.URL+"/api", WithRetryPolicy(testRetryPolicy()), WithMetrics(metrics))
+	ctx := context.Background()
+
+	if _, err := c.GetEngineer(ctx, "7"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.GetEngineers(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "devops.prom")
+	if err := metrics.WriteFile(file); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`devops_api_requests_total{code="503",method="GET",route="/engineers"} 1`,
+		`devops_api_requests_total{code="200",method="GET",route="/engineers"} 1`,
+		`devops_api_requests_total{code="200",method="GET",route="/engineers/{id}"} 1`,
+		`devops_api_retries_total{method="GET",route="/engineers"} 1`,
+		`devops_api_request_duration_seconds_count{method="GET",route="/engineers"} 2`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %q in:\n%s", expected, data)
+		}
+	}
+}
+
+func TestMetricsWriteFileAddsUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dev" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "devops.prom")
+
+	// Like the plan and apply walks of one terraform apply, each in its
+	// own provider process with its own metrics.
+	plan := NewMetrics()
+	c, _ := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}), WithMetrics(plan))
+	if _, err := c.GetEngineers(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.GetDevelopers(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := plan.WriteFile(file); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	apply := NewMetrics()
+	c, _ = NewClient(server.URL, WithMetrics(apply))
+	if _, err := c.GetEngineers(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := apply.WriteFile(file); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`devops_api_requests_total{code="200",method="GET",route="/engineers"} 2`,
+		`devops_api_requests_total{code="503",method="GET",route="/dev"} 1`,
+		`devops_api_request_duration_seconds_count{method="GET",route="/engineers"} 2`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %q in:\n%s", expected, data)
+		}
+	}
+}
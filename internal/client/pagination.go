@@ -0,0 +1,234 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// WithPageSize asks the API for pages of the given size by adding a limit
+// query parameter to collection requests. Zero leaves the page size to the
+// server.
+func WithPageSize(size int) Option {
+	return func(c *Client) {
+		c.pageSize = size
+	}
+}
+
+// pageEnvelope is the object form of a paginated collection response.
+// Servers that return a bare JSON array instead paginate through Link
+// headers only.
+type pageEnvelope[T any] struct {
+	Data       []T    `json:"data"`
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// paginate streams every item of the collection at collectionURL, following
+// Link rel="next" headers and next_cursor fields until the last page.
+func paginate[T any](ctx context.Context, c *Client, collectionURL string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		pageURL, err := url.Parse(collectionURL)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		if c.pageSize > 0 {
+			query := pageURL.Query()
+			query.Set("limit", strconv.Itoa(c.pageSize))
+			pageURL.RawQuery = query.Encode()
+		}
+
+		seen := map[string]bool{}
+		for pageURL != nil {
+			if seen[pageURL.String()] {
+				yield(zero, fmt.Errorf("pagination loop detected at %s", pageURL.Redacted()))
+				return
+			}
+			seen[pageURL.String()] = true
+
+			items, next, err := fetchPage[T](ctx, c, pageURL)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			pageURL = next
+		}
+	}
+}
+
+// collect drains a paginated sequence into a slice.
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	items := []T{}
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// fetchPage downloads one page and works out the URL of the next one, if
+// any.
+func fetchPage[T any](ctx context.Context, c *Client, pageURL *url.URL) ([]T, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var items []T
+	var cursor string
+
+	res, err := c.doRequestDecode(req, func(_ *http.Response, body io.Reader) error {
+		var err error
+		items, cursor, err = decodePage[T](body)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if link := nextLink(res.Header.Values("Link")); link != "" {
+		next, err := pageURL.Parse(link)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid Link header %q: %w", link, err)
+		}
+		// The next page is fetched with the client's credentials, so it
+		// must not lead anywhere but the API itself.
+		if !c.ownsURL(next) {
+			return nil, nil, fmt.Errorf("Link header points to %s, outside the configured endpoints", next.Redacted())
+		}
+		return items, next, nil
+	}
+
+	if cursor != "" {
+		next := *pageURL
+		query := next.Query()
+		query.Set("cursor", cursor)
+		next.RawQuery = query.Encode()
+		return items, &next, nil
+	}
+
+	return items, nil, nil
+}
+
+// decodePage decodes one page, given either as a bare JSON array or as a
+// pageEnvelope. Arrays are decoded element by element so the raw page is
+// never held in memory alongside the decoded items.
+func decodePage[T any](body io.Reader) ([]T, string, error) {
+	reader := bufio.NewReader(body)
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		return nil, "", err
+	}
+
+	decoder := json.NewDecoder(reader)
+
+	if first == '{' {
+		var envelope pageEnvelope[T]
+		if err := decoder.Decode(&envelope); err != nil {
+			return nil, "", err
+		}
+		if envelope.Data != nil {
+			return envelope.Data, envelope.NextCursor, nil
+		}
+		return envelope.Items, envelope.NextCursor, nil
+	}
+
+	if first != '[' {
+		// Not a collection; let the decoder report what it is instead.
+		var items []T
+		err := decoder.Decode(&items)
+		return items, "", err
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, "", err
+	}
+
+	items := []T{}
+	for decoder.More() {
+		var item T
+		if err := decoder.Decode(&item); err != nil {
+			return nil, "", err
+		}
+		items = append(items, item)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, "", err
+	}
+
+	return items, "", nil
+}
+
+// peekNonSpace returns the first non-whitespace byte of reader without
+// consuming it.
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			if _, err := reader.ReadByte(); err != nil {
+				return 0, err
+			}
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+// nextLink returns the target of the rel="next" entry in RFC 8288 Link
+// header values.
+func nextLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok {
+				continue
+			}
+
+			target = strings.TrimSpace(target)
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(val, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
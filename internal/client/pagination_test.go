@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetEngineersFollowsLinkHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("limit"); got != "1" {
+			t.Errorf("expected limit=1, got %q", got)
+		}
+
+		switch page := r.URL.Query().Get("page"); page {
+		case "":
+			w.Header().Set("Link", `</engineers?page=2&limit=1>; rel="next", </engineers?page=2&limit=1>; rel="last"`)
+			_, _ = w.Write([]byte(`[{"id":"1","name":"Ada","email":"ada@example.com"}]`))
+		case "2":
+			w.Header().Set("Link", `</engineers?limit=1>; rel="first"`)
+			_, _ = w.Write([]byte(`[{"id":"2","name":"Grace","email":"grace@example.com"}]`))
+		default:
+			t.Errorf("unexpected page %q", page)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithPageSize(1))
+
+	engineers, err := c.GetEngineers(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(engineers) != 2 || engineers[1].ID != "2" {
+		t.Fatalf("expected both pages, got %+v", engineers)
+	}
+}
+
+func TestGetEngineersRejectsForeignLink(t *testing.T) {
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("followed the Link header to another host with Authorization %q", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer foreign.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", "<"+foreign.URL+`/engineers?page=2>; rel="next"`)
+		_, _ = w.Write([]byte(`[{"id":"1","name":"Ada","email":"ada@example.com"}]`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithToken("secret"))
+
+	if _, err := c.GetEngineers(context.Background()); err == nil || !strings.Contains(err.Error(), "outside the configured endpoints") {
+		t.Fatalf("expected the foreign link to be rejected, got %v", err)
+	}
+}
+
+func TestDevelopersFollowsNextCursor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		next := ""
+		switch cursor {
+		case "":
+			next = "abc"
+		case "abc":
+			next = "def"
+		}
+		fmt.Fprintf(w, `{"data":[{"id":%q,"name":"team","engineers":[]}],"next_cursor":%q}`, "dev-"+cursor, next)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL)
+
+	var ids []string
+	for developer, err := range c.Developers(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		ids = append(ids, developer.ID)
+	}
+
+	if fmt.Sprint(ids) != "[dev- dev-abc dev-def]" {
+		t.Fatalf("unexpected developers %v", ids)
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	testCases := map[string]string{
+		`<https://api/engineers?page=2>; rel="next"`:                           "https://api/engineers?page=2",
+		`<https://api/e?page=1>; rel="prev", <https://api/e?page=3>; rel=next`: "https://api/e?page=3",
+		`<https://api/e?page=9>; rel="last"`:                                   "",
+		``:                                                                     "",
+	}
+
+	for header, expected := range testCases {
+		if got := nextLink([]string{header}); got != expected {
+			t.Errorf("nextLink(%q) = %q, expected %q", header, got, expected)
+		}
+	}
+}
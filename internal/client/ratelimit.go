@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit caps the client at requestsPerSecond requests, shared by
+// every resource and data source using this client. Zero disables the
+// limit.
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		burst := max(1, int(math.Ceil(requestsPerSecond)))
+		c.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+}
+
+// WithMaxConcurrentRequests caps the number of requests in flight at once.
+// Zero disables the cap.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(c *Client) {
+		if n <= 0 {
+			c.slots = nil
+			return
+		}
+		c.slots = make(chan struct{}, n)
+	}
+}
+
+// acquire blocks until the rate limit and concurrency cap allow another
+// request. The returned function must be called once the request is done.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if c.slots != nil {
+			<-c.slots
+		}
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
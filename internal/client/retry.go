@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Default retry settings used when the provider configuration does not
+// override them.
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryWaitMin = 1 * time.Second
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the initial attempt.
+	// Zero disables retries.
+	MaxRetries int
+
+	// WaitMin and WaitMax bound the backoff between attempts. A
+	// Retry-After header sent by the API is honored up to WaitMax.
+	WaitMin time.Duration
+	WaitMax time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		WaitMin:    DefaultRetryWaitMin,
+		WaitMax:    DefaultRetryWaitMax,
+	}
+}
+
+// retryableStatus reports whether a response status is worth retrying.
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// idempotent reports whether sending req twice has the same effect as
+// sending it once. Conditional updates are not: when the first one was
+// applied, the second fails its If-Match check. A conditional delete is,
+// as the second one finds the item gone, which Delete accepts.
+func idempotent(req *http.Request) bool {
+	if conditionalUpdate(req) {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPatch:
+		return req.Header.Get("Content-Type") == mergePatchContentType
+	}
+	return false
+}
+
+// conditionalUpdate reports whether req is a PUT or PATCH guarded by
+// If-Match.
+func conditionalUpdate(req *http.Request) bool {
+	return (req.Method == http.MethodPut || req.Method == http.MethodPatch) && req.Header.Get("If-Match") != ""
+}
+
+// retrySafe reports whether req may be sent again after an attempt that may
+// have reached the API. Idempotent requests are always safe, and so are
+// conditional updates, whose callers check a 412 after a resend against
+// the item on the server. Creates are only safe when they carry an
+// Idempotency-Key the client generated for them.
+func retrySafe(req *http.Request) bool {
+	return idempotent(req) || conditionalUpdate(req) || deliveryOf(req).wasKeyed()
+}
+
+// shouldRetry decides whether an attempt that produced res and err should
+// be repeated.
+func shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if res == nil {
+		// Certificate problems will not fix themselves between attempts.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			var unknownAuthority x509.UnknownAuthorityError
+			var hostname x509.HostnameError
+			var invalid x509.CertificateInvalidError
+			if errors.As(urlErr.Err, &unknownAuthority) ||
+				errors.As(urlErr.Err, &hostname) ||
+				errors.As(urlErr.Err, &invalid) {
+				return false
+			}
+		}
+		// A request that was never written can always be sent again.
+		return !deliveryOf(req).wasWritten() || retrySafe(req)
+	}
+
+	if !retryableStatus(res.StatusCode) {
+		return false
+	}
+
+	// A 429 means the API rejected the request without processing it,
+	// so even non-idempotent requests can be sent again.
+	return res.StatusCode == http.StatusTooManyRequests || retrySafe(req)
+}
+
+// backoff returns how long to wait before retry number attempt (starting
+// at zero), preferring the API's Retry-After hint when present.
+func (p RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if wait, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return min(wait, p.WaitMax)
+		}
+	}
+
+	wait := p.WaitMin
+	for i := 0; i < attempt && wait < p.WaitMax; i++ {
+		wait *= 2
+	}
+	wait = min(wait, p.WaitMax)
+
+	// Apply jitter in [wait/2, wait] so parallel resources spread out.
+	if half := wait / 2; half > 0 {
+		wait = half + rand.N(half+1)
+	}
+
+	return wait
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or
+// as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
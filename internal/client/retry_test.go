@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxRetries: 3, WaitMin: time.Millisecond, WaitMax: 5 * time.Millisecond}
+}
+
+func TestDoRequestRetriesTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":"1","name":"Ada","email":"ada@example.com"}]`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+
+	engineers, err := c.GetEngineers(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(engineers) != 1 {
+		t.Fatalf("expected 1 engineer, got %d", len(engineers))
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected 3 calls, got %d", got)
+	}
+}
+
+func TestDoRequestDoesNotRetryUnsafePost(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+
+	// A POST without an Idempotency-Key may have been applied already.
+	req, _ := http.NewRequestWithContext(context.Background(), "POST", c.url("engineers"), strings.NewReader(`{"name":"Ada"}`))
+	if _, err := c.doRequest(req); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 call, got %d", got)
+	}
+}
+
+// appliedUpdateServer applies the first conditional update of engineer 1
+// but loses its response, so a resent update fails its If-Match check.
+// stored is the engineer the server ends up with.
+func appliedUpdateServer(t *testing.T, stored string) *httptest.Server {
+	t.Helper()
+
+	var applied atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			w.Header().Set("ETag", `"v3"`)
+			_, _ = w.Write([]byte(stored))
+		case applied.CompareAndSwap(false, true):
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusPreconditionFailed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestConditionalUpdateConfirmedAfterResend(t *testing.T) {
+	server := appliedUpdateServer(t, `{"id":"1","name":"Ada","email":"ada@example.com"}`)
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+
+	// The 412 is caused by the first attempt, which the read shows.
+	engineer, err := c.UpdateEngineer(context.Background(), "1", Engineer{ID: "1", Name: "Ada", Email: "ada@example.com", ETag: `"v1"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if engineer.ETag != `"v3"` {
+		t.Errorf("expected the ETag of the current engineer, got %q", engineer.ETag)
+	}
+}
+
+func TestConditionalUpdateConflictAfterResend(t *testing.T) {
+	server := appliedUpdateServer(t, `{"id":"1","name":"Grace","email":"ada@example.com"}`)
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+
+	// Someone else changed the engineer, so the conflict is real.
+	name := "Ada"
+	_, err := c.PatchEngineer(context.Background(), "1", EngineerPatch{Name: &name, ETag: `"v1"`})
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestConditionalDeleteRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		// The first delete was applied after all.
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	if err := c.DeleteEngineer(context.Background(), "1", `"v1"`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestDoRequestIgnoresStaticIdempotencyKey(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	// A key that is the same for every request says nothing about whether
+	// the API has applied this one.
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()), WithHeaders(map[string]string{"Idempotency-Key": "static"}))
+
+	req, _ := http.NewRequestWithContext(context.Background(), "POST", c.url("engineers"), strings.NewReader(`{"name":"Ada"}`))
+	if _, err := c.doRequest(req); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 call, got %d", got)
+	}
+}
+
+func TestDoRequestRetriesUnsentConditionalWrite(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`{"id":"1","name":"Ada","email":"ada@example.com"}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	var refused atomic.Bool
+	c.HTTPClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		// The first connection attempt fails before anything is written.
+		if refused.CompareAndSwap(false, true) {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	if _, err := c.UpdateEngineer(context.Background(), "1", Engineer{ID: "1", Name: "Ada", Email: "ada@example.com", ETag: `"v1"`}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected the update to reach the server once, got %d", got)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{WaitMin: 100 * time.Millisecond, WaitMax: time.Second}
+
+	for attempt := 0; attempt < 6; attempt++ {
+		wait := policy.backoff(attempt, nil)
+		if wait < policy.WaitMin/2 || wait > policy.WaitMax {
+			t.Errorf("attempt %d: backoff %s outside [%s, %s]", attempt, wait, policy.WaitMin/2, policy.WaitMax)
+		}
+	}
+
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"120"}}}
+	if wait := policy.backoff(0, res); wait != policy.WaitMax {
+		t.Errorf("expected Retry-After capped at %s, got %s", policy.WaitMax, wait)
+	}
+
+	res.Header.Set("Retry-After", "0")
+	if wait := policy.backoff(0, res); wait != 0 {
+		t.Errorf("expected Retry-After of 0, got %s", wait)
+	}
+}
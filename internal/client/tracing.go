@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the client's spans to OpenTelemetry.
+const tracerName = "github.com/madisonewebb/DOB-tf-providers/internal/client"
+
+// propagator writes the W3C traceparent, tracestate and baggage headers,
+// so the API's spans join the trace of the request that caused them.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// WithTracerProvider makes the client record its spans with tp instead of
+// the global OpenTelemetry tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// defaultTracer returns the client tracer of the global tracer provider,
+// which also picks up a provider installed after the client was created.
+func defaultTracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// startSpan starts the client span of one attempt of req and propagates
+// its trace context in the request headers. Retries are separate spans,
+// told apart by their resend count.
+func (c *Client) startSpan(ctx context.Context, req *http.Request, attempt int) (context.Context, trace.Span) {
+	route := c.route(req.URL)
+	attributes := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", req.URL.Redacted()),
+		attribute.String("url.template", route),
+		attribute.String("server.address", req.URL.Hostname()),
+	}
+	if attempt > 0 {
+		attributes = append(attributes, attribute.Int("http.request.resend_count", attempt))
+	}
+
+	ctx, span := c.tracer.Start(ctx, req.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return ctx, span
+}
+
+// endSpan records the outcome of an attempt on its span and ends it.
+func endSpan(span trace.Span, res *http.Response, err error) {
+	defer span.End()
+
+	if res != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
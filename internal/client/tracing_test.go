@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	var mu sync.Mutex
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		traceparents = append(traceparents, r.Header.Get("Traceparent"))
+		if len(traceparents) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"7","name":"Ada","email":"ada@example.com"}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	c, _ := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()), WithTracerProvider(tp))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "apply")
+	if _, err := c.GetEngineer(ctx, "7"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected two attempt spans and the parent, got %d", len(spans))
+	}
+	for i, span := range spans[:2] {
+		if span.Name() != "GET /engineers/{id}" || span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("unexpected span %q of kind %s", span.Name(), span.SpanKind())
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("expected attempt %d to be a child of the caller's span", i)
+		}
+
+		expected := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"
+		if traceparents[i] != expected {
+			t.Errorf("expected traceparent %q for attempt %d, got %q", expected, i, traceparents[i])
+		}
+	}
+	if spans[0].Status().Code.String() != "Error" {
+		t.Errorf("expected the failed attempt to be marked as an error, got %s", spans[0].Status().Code)
+	}
+}
@@ -0,0 +1,139 @@
+package fakeapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+	"github.com/madisonewebb/DOB-tf-providers/internal/fakeapi"
+)
+
+func TestServerWithClient(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	c, err := client.NewClient(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx := context.Background()
+
+	capabilities, err := c.Probe(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := client.Capabilities{APIVersion: fakeapi.APIVersion, Patch: client.Supported, SingleItemGet: client.Supported, IdempotencyKeys: client.Supported}
+	if capabilities != expected {
+		t.Fatalf("expected %+v, got %+v", expected, capabilities)
+	}
+
+	engineer, err := c.CreateEngineer(ctx, client.Engineer{Name: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if engineer.ID == "" || engineer.ETag == "" {
+		t.Fatalf("expected ID and ETag, got %+v", engineer)
+	}
+
+	if _, err := c.CreateEngineer(ctx, client.Engineer{Name: "Bad", Email: "not-an-email"}); !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+
+	developer, err := c.CreateDeveloper(ctx, client.Developer{Name: "Frontend", Engineers: []client.Engineer{*engineer}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	name := "Backend"
+	developer, err = c.PatchDeveloper(ctx, developer.ID, client.DeveloperPatch{Name: &name, ETag: developer.ETag})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if developer.Name != "Backend" || len(developer.Engineers) != 1 {
+		t.Fatalf("expected rename to keep membership, got %+v", developer)
+	}
+
+	if _, err := c.PatchDeveloper(ctx, developer.ID, client.DeveloperPatch{Name: &name, ETag: `"stale"`}); !errors.Is(err, client.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	if err := c.DeleteEngineer(ctx, engineer.ID, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.GetEngineer(ctx, engineer.ID); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if got := len(server.Engineers()); got != 0 {
+		t.Fatalf("expected no engineers left, got %d", got)
+	}
+}
+
+func TestServerReplaysIdempotentCreates(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	create := func() string {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/engineers", strings.NewReader(`{"name":"Ada","email":"ada@example.com"}`))
+		req.Header.Set("Idempotency-Key", "create-ada")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		defer res.Body.Close()
+
+		var engineer client.Engineer
+		if err := json.NewDecoder(res.Body).Decode(&engineer); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return engineer.ID
+	}
+
+	if first, second := create(), create(); first != second {
+		t.Errorf("expected the repeated create to return engineer %s, got %s", first, second)
+	}
+	if got := len(server.Engineers()); got != 1 {
+		t.Errorf("expected 1 engineer, got %d", got)
+	}
+}
+
+func TestServerOpsAndDevOps(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	c, _ := client.NewClient(server.URL)
+	ctx := context.Background()
+
+	engineer := server.AddEngineer(client.Engineer{Name: "Ada", Email: "ada@example.com"})
+	ops, err := c.Ops().Create(ctx, client.Ops{Name: "SRE", Engineers: []client.Engineer{engineer}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	devops, err := c.DevOps().Create(ctx, client.DevOps{Ops: []client.Ops{*ops}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	name := "Platform"
+	if _, err := c.Ops().Patch(ctx, ops.ID, client.OpsPatch{Name: &name, ETag: ops.ETag}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	groups, err := c.DevOps().List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(groups) != 1 || groups[0].ID != devops.ID || len(groups[0].Ops) != 1 || len(groups[0].Dev) != 0 {
+		t.Fatalf("expected the created group, got %+v", groups)
+	}
+
+	if err := c.DevOps().Delete(ctx, devops.ID, devops.ETag); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.DevOps().Get(ctx, devops.ID); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
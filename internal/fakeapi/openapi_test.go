@@ -0,0 +1,460 @@
+package fakeapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+	"github.com/madisonewebb/DOB-tf-providers/internal/fakeapi"
+)
+
+// specPath is the checked-in API contract that the client types and the
+// fake server are held to.
+const specPath = "../../openapi.json"
+
+// apiDocPath documents the real API, which the contract is held to.
+const apiDocPath = "../../API.md"
+
+// openAPI is the subset of an OpenAPI 3.0 document these tests read.
+type openAPI struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	RequestBody struct {
+		Content map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]json.RawMessage `json:"responses"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Example              json.RawMessage    `json:"example"`
+	Extension            string             `json:"x-extension"`
+}
+
+func loadSpec(t *testing.T) *openAPI {
+	t.Helper()
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Fatalf("reading %s: %s", specPath, err)
+	}
+
+	var spec openAPI
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("parsing %s: %s", specPath, err)
+	}
+	return &spec
+}
+
+// resolve follows a local $ref to the schema it names.
+func (spec *openAPI) resolve(t *testing.T, s *schema) (string, *schema) {
+	t.Helper()
+
+	if s.Ref == "" {
+		return "", s
+	}
+	name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	resolved, ok := spec.Components.Schemas[name]
+	if !ok {
+		t.Fatalf("unresolved reference %s", s.Ref)
+	}
+	return name, resolved
+}
+
+func (spec *openAPI) operation(t *testing.T, path, method string) *operation {
+	t.Helper()
+
+	raw, ok := spec.Paths[path][strings.ToLower(method)]
+	if !ok {
+		t.Fatalf("the spec does not document %s %s", method, path)
+	}
+	var op operation
+	if err := json.Unmarshal(raw, &op); err != nil {
+		t.Fatalf("parsing %s %s: %s", method, path, err)
+	}
+	return &op
+}
+
+// validate checks value, decoded from JSON, against s and returns the
+// problems found, each prefixed with its location.
+func (spec *openAPI) validate(t *testing.T, at string, value interface{}, s *schema) []string {
+	t.Helper()
+
+	_, s = spec.resolve(t, s)
+
+	var problems []string
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an object, got %T", at, value)}
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %q", at, name))
+			}
+		}
+		for name, field := range object {
+			property, ok := s.Properties[name]
+			if !ok {
+				if string(s.AdditionalProperties) == "false" {
+					problems = append(problems, fmt.Sprintf("%s: undocumented property %q", at, name))
+				}
+				continue
+			}
+			problems = append(problems, spec.validate(t, at+"."+name, field, property)...)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an array, got %T", at, value)}
+		}
+		for i, item := range items {
+			problems = append(problems, spec.validate(t, fmt.Sprintf("%s[%d]", at, i), item, s.Items)...)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			problems = append(problems, fmt.Sprintf("%s: expected a string, got %T", at, value))
+		}
+	}
+	return problems
+}
+
+// apiDoc is what API.md says about the real API.
+type apiDoc struct {
+	// fields lists the fields of each entity by the name of its schema.
+	fields map[string][]string
+	// routes lists operations such as "GET /engineers".
+	routes []string
+	// extensions names the undocumented features the spec may describe.
+	extensions []string
+}
+
+// docEntities maps the entity names used in API.md to schema names.
+var docEntities = map[string]string{
+	"Engineer":   "Engineer",
+	"Developer":  "Developer",
+	"Operations": "Ops",
+	"DevOps":     "DevOps",
+}
+
+var (
+	docEntity    = regexp.MustCompile(`^\d+\. (\w+) - `)
+	docField     = regexp.MustCompile(`^\s+- (\w+) \(`)
+	docRoute     = regexp.MustCompile("^- `([A-Z]+ /\\S+)`")
+	docExtension = regexp.MustCompile("^- `([a-z-]+)`:")
+)
+
+func loadAPIDoc(t *testing.T) *apiDoc {
+	t.Helper()
+
+	data, err := os.ReadFile(apiDocPath)
+	if err != nil {
+		t.Fatalf("reading %s: %s", apiDocPath, err)
+	}
+
+	doc := &apiDoc{fields: map[string][]string{}}
+	var entity string
+	for _, line := range strings.Split(string(data), "\n") {
+		if match := docEntity.FindStringSubmatch(line); match != nil {
+			name, ok := docEntities[match[1]]
+			if !ok {
+				t.Fatalf("%s documents entity %s, which has no schema", apiDocPath, match[1])
+			}
+			entity = name
+			continue
+		}
+		if match := docField.FindStringSubmatch(line); match != nil && entity != "" {
+			doc.fields[entity] = append(doc.fields[entity], match[1])
+			continue
+		}
+		entity = ""
+		if match := docRoute.FindStringSubmatch(line); match != nil {
+			doc.routes = append(doc.routes, match[1])
+		} else if match := docExtension.FindStringSubmatch(line); match != nil {
+			doc.extensions = append(doc.extensions, match[1])
+		}
+	}
+	return doc
+}
+
+// extensionNames returns every x-extension value in the raw document.
+func extensionNames(v interface{}) []string {
+	var names []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if name, ok := value.(string); ok && key == "x-extension" {
+				names = append(names, name)
+				continue
+			}
+			names = append(names, extensionNames(value)...)
+		}
+	case []interface{}:
+		for _, value := range v {
+			names = append(names, extensionNames(value)...)
+		}
+	}
+	return names
+}
+
+// TestSpecMatchesAPIDoc keeps the spec honest about the real API: what it
+// describes beyond API.md has to be marked as an extension.
+func TestSpecMatchesAPIDoc(t *testing.T) {
+	spec := loadSpec(t)
+	doc := loadAPIDoc(t)
+
+	for _, schemaName := range docEntities {
+		s := spec.Components.Schemas[schemaName]
+		var properties []string
+		for name, property := range s.Properties {
+			if property.Extension == "" {
+				properties = append(properties, name)
+			}
+		}
+		slices.Sort(properties)
+		documented := slices.Sorted(slices.Values(doc.fields[schemaName]))
+		if !slices.Equal(properties, documented) {
+			t.Errorf("%s: the spec has properties %v without x-extension, API.md documents %v", schemaName, properties, documented)
+		}
+	}
+
+	var routes []string
+	for path, operations := range spec.Paths {
+		for method, raw := range operations {
+			if method == "parameters" {
+				continue
+			}
+			var op struct {
+				Extension string `json:"x-extension"`
+			}
+			if err := json.Unmarshal(raw, &op); err != nil {
+				t.Fatalf("parsing %s %s: %s", method, path, err)
+			}
+			if op.Extension == "" {
+				routes = append(routes, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	slices.Sort(routes)
+	documented := slices.Sorted(slices.Values(doc.routes))
+	if !slices.Equal(routes, documented) {
+		t.Errorf("the spec has operations %v without x-extension, API.md documents %v", routes, documented)
+	}
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Fatalf("reading %s: %s", specPath, err)
+	}
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("parsing %s: %s", specPath, err)
+	}
+	for _, name := range extensionNames(raw) {
+		if !slices.Contains(doc.extensions, name) {
+			t.Errorf("the spec marks extension %q, which API.md does not list", name)
+		}
+	}
+}
+
+// specTypes maps every schema the client models to its Go type.
+var specTypes = []interface{}{
+	client.Engineer{},
+	client.Developer{},
+	client.Ops{},
+	client.DevOps{},
+	client.EngineerPatch{},
+	client.DeveloperPatch{},
+	client.OpsPatch{},
+	client.DevOpsPatch{},
+}
+
+func TestClientTypesMatchSpec(t *testing.T) {
+	spec := loadSpec(t)
+
+	for _, v := range specTypes {
+		typ := reflect.TypeOf(v)
+		t.Run(typ.Name(), func(t *testing.T) {
+			s, ok := spec.Components.Schemas[typ.Name()]
+			if !ok {
+				t.Fatalf("no schema named %s", typ.Name())
+			}
+
+			var fields []string
+			for i := range typ.NumField() {
+				field := typ.Field(i)
+				name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if name == "-" || !field.IsExported() {
+					continue
+				}
+				fields = append(fields, name)
+
+				property, ok := s.Properties[name]
+				if !ok {
+					t.Errorf("field %s is serialised as %q, which the spec does not document", field.Name, name)
+					continue
+				}
+				if problem := spec.matchType(t, field.Type, property); problem != "" {
+					t.Errorf("field %s: %s", field.Name, problem)
+				}
+			}
+
+			for name := range s.Properties {
+				if !slices.Contains(fields, name) {
+					t.Errorf("property %q is not modelled by %s", name, typ.Name())
+				}
+			}
+		})
+	}
+}
+
+// matchType reports how a Go field type differs from a schema property.
+func (spec *openAPI) matchType(t *testing.T, typ reflect.Type, property *schema) string {
+	t.Helper()
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	name, resolved := spec.resolve(t, property)
+	switch typ.Kind() {
+	case reflect.String:
+		if resolved.Type != "string" {
+			return fmt.Sprintf("string field for a property of type %q", resolved.Type)
+		}
+	case reflect.Slice:
+		if resolved.Type != "array" {
+			return fmt.Sprintf("slice field for a property of type %q", resolved.Type)
+		}
+		return spec.matchType(t, typ.Elem(), resolved.Items)
+	case reflect.Struct:
+		if name != typ.Name() {
+			return fmt.Sprintf("%s field for a property of schema %q", typ.Name(), name)
+		}
+	default:
+		return fmt.Sprintf("unexpected field kind %s", typ.Kind())
+	}
+	return ""
+}
+
+func TestFakeServerMatchesSpec(t *testing.T) {
+	spec := loadSpec(t)
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	// call sends a documented request and checks the response against the
+	// spec, returning the decoded body.
+	call := func(t *testing.T, method, path, route string, body json.RawMessage) (int, interface{}) {
+		t.Helper()
+
+		op := spec.operation(t, route, method)
+
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+
+		req, _ := http.NewRequest(method, server.URL+path, reqBody)
+		for contentType := range op.RequestBody.Content {
+			req.Header.Set("Content-Type", contentType)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %s", method, path, err)
+		}
+		defer res.Body.Close()
+
+		status := strconv.Itoa(res.StatusCode)
+		rawResponse, ok := op.Responses[status]
+		if !ok {
+			t.Fatalf("%s %s: undocumented status %s", method, path, status)
+		}
+
+		data, _ := io.ReadAll(res.Body)
+		if len(bytes.TrimSpace(data)) == 0 {
+			return res.StatusCode, nil
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("%s %s: %s", method, path, err)
+		}
+
+		var response struct {
+			Content map[string]struct {
+				Schema *schema `json:"schema"`
+			} `json:"content"`
+		}
+		_ = json.Unmarshal(rawResponse, &response)
+		if media, ok := response.Content["application/json"]; ok && media.Schema != nil {
+			for _, problem := range spec.validate(t, method+" "+path, decoded, media.Schema) {
+				t.Error(problem)
+			}
+		}
+		return res.StatusCode, decoded
+	}
+
+	collections := map[string]string{
+		"/engineers": "Engineer",
+		"/dev":       "Developer",
+		"/ops":       "Ops",
+		"/devops":    "DevOps",
+	}
+
+	for path := range spec.Paths {
+		if _, ok := collections[strings.TrimSuffix(path, "/{id}")]; !ok && path != "/version" && path != "/health" {
+			t.Errorf("the spec documents %s, which this test does not cover", path)
+		}
+	}
+
+	for collection, schemaName := range collections {
+		t.Run(collection, func(t *testing.T) {
+			example := spec.Components.Schemas[schemaName].Example
+			patch := spec.Components.Schemas[schemaName+"Patch"].Example
+			if example == nil || patch == nil {
+				t.Fatalf("%s and %sPatch need examples", schemaName, schemaName)
+			}
+			item := collection + "/{id}"
+
+			_, created := call(t, "POST", collection, collection, example)
+			id := created.(map[string]interface{})["id"].(string)
+
+			call(t, "GET", collection, collection, nil)
+			call(t, "GET", collection+"/"+id, item, nil)
+			call(t, "PATCH", collection+"/"+id, item, patch)
+			call(t, "PUT", collection+"/"+id, item, example)
+			call(t, "DELETE", collection+"/"+id, item, nil)
+
+			if status, _ := call(t, "GET", collection+"/"+id, item, nil); status != http.StatusNotFound {
+				t.Errorf("expected the deleted item to be gone, got status %d", status)
+			}
+		})
+	}
+
+	for _, path := range []string{"/version", "/health"} {
+		if status, _ := call(t, "GET", path, path, nil); status != http.StatusOK {
+			t.Errorf("GET %s: status %d", path, status)
+		}
+	}
+}
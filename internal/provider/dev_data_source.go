@@ -72,14 +72,18 @@ func (d *developersDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 
 // Read refreshes the Terraform state with the latest data.
 func (d *developersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := startSpan(ctx, "data.devops-bootcamp_developers Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Get developers from the API
-	developers, err := d.client.GetDevelopers()
+	developers, err := d.client.GetDevelopers(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(&resp.Diagnostics,
 			"Unable to Read DevOps Developers",
 			"An unexpected error occurred when reading the DevOps developers. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
-				"DevOps Client Error: "+err.Error(),
+				"DevOps Client Error: ",
+			err,
 		)
 		return
 	}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -79,6 +80,9 @@ func (r *devResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *devResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "devops-bootcamp_dev Create")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Retrieve values from plan
 	var plan devResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -94,11 +98,12 @@ func (r *devResource) Create(ctx context.Context, req resource.CreateRequest, re
 	}
 
 	// Create developer via API
-	createdDeveloper, err := r.client.CreateDeveloper(developer)
+	createdDeveloper, err := r.client.CreateDeveloper(ctx, developer)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(&resp.Diagnostics,
 			"Error Creating Developer",
-			"Could not create developer, unexpected error: "+err.Error(),
+			"Could not create developer, unexpected error: ",
+			err, "name",
 		)
 		return
 	}
@@ -146,10 +151,16 @@ func (r *devResource) Create(ctx context.Context, req resource.CreateRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// Remember the ETag so later updates and deletes are conditional
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, createdDeveloper.ETag)...)
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (r *devResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "devops-bootcamp_dev Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Get current state
 	var state devResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -159,12 +170,21 @@ func (r *devResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	}
 
 	// Get refreshed developer value from API
-	developer, err := r.client.GetDeveloper(state.ID.ValueString())
+	developer, err := r.client.GetDeveloper(ctx, state.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Reading Developer",
-			"Could not read developer ID "+state.ID.ValueString()+": "+err.Error(),
-		)
+		if errors.Is(err, client.ErrNotFound) {
+			resp.State.RemoveResource(ctx)
+			resp.Diagnostics.AddWarning(
+				"Developer Missing",
+				fmt.Sprintf("Developer with ID %s no longer exists. Removing from state.", state.ID.ValueString()),
+			)
+		} else {
+			addAPIErrorDiagnostics(&resp.Diagnostics,
+				"Error Reading Developer",
+				"Could not read developer ID "+state.ID.ValueString()+": ",
+				err,
+			)
+		}
 		return
 	}
 
@@ -211,10 +231,16 @@ func (r *devResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// Track the ETag of the refreshed developer
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, developer.ETag)...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *devResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "devops-bootcamp_dev Update")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Retrieve values from plan
 	var plan devResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -223,7 +249,7 @@ func (r *devResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
-	// Get current state to preserve engineers list
+	// Get current state to detect changed fields
 	var state devResourceModel
 	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -231,42 +257,57 @@ func (r *devResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
-	// Convert engineers from state back to client format
-	var engineers []client.Engineer
-	
-	// Extract engineers from state.Engineers (types.List)
-	if !state.Engineers.IsNull() && !state.Engineers.IsUnknown() {
-		var stateEngineers []map[string]interface{}
-		diags = state.Engineers.ElementsAs(ctx, &stateEngineers, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		
-		// Convert to client.Engineer format
-		for _, eng := range stateEngineers {
-			engineer := client.Engineer{
-				ID:    eng["id"].(string),
-				Name:  eng["name"].(string),
-				Email: eng["email"].(string),
-			}
-			engineers = append(engineers, engineer)
-		}
+	// Only apply the update if nobody changed the developer since the last read
+	etag, diags := getPrivateETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Update existing developer
-	developer := client.Developer{
-		ID:        plan.ID.ValueString(),
-		Name:      plan.Name.ValueString(),
-		Engineers: engineers, // Preserve existing engineers
+	// Send only the changed fields so a rename never reverts team
+	// membership changes made since the last refresh
+	patch := client.DeveloperPatch{ETag: etag}
+	if !plan.Name.Equal(state.Name) {
+		patch.Name = plan.Name.ValueStringPointer()
 	}
 
 	// Update developer via API
-	updatedDeveloper, err := r.client.UpdateDeveloper(plan.ID.ValueString(), developer)
+	updatedDeveloper, err := r.client.PatchDeveloper(ctx, plan.ID.ValueString(), patch)
+	if errors.Is(err, client.ErrPatchNotSupported) {
+		// The API only supports full updates, so convert the engineers
+		// from state back to client format to preserve them
+		var engineers []client.Engineer
+		if !state.Engineers.IsNull() && !state.Engineers.IsUnknown() {
+			var stateEngineers []devEngineerModel
+			diags = state.Engineers.ElementsAs(ctx, &stateEngineers, false)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			for _, eng := range stateEngineers {
+				engineers = append(engineers, client.Engineer{
+					ID:    eng.ID.ValueString(),
+					Name:  eng.Name.ValueString(),
+					Email: eng.Email.ValueString(),
+				})
+			}
+		}
+
+		developer := client.Developer{
+			ID:        plan.ID.ValueString(),
+			Name:      plan.Name.ValueString(),
+			Engineers: engineers,
+			ETag:      etag,
+		}
+
+		updatedDeveloper, err = r.client.UpdateDeveloper(ctx, plan.ID.ValueString(), developer)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(&resp.Diagnostics,
 			"Error Updating Developer",
-			"Could not update developer, unexpected error: "+err.Error(),
+			"Could not update developer, unexpected error: ",
+			err, "name",
 		)
 		return
 	}
@@ -313,10 +354,16 @@ func (r *devResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// Track the ETag of the updated developer
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, updatedDeveloper.ETag)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *devResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startSpan(ctx, "devops-bootcamp_dev Delete")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Retrieve values from state
 	var state devResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -325,12 +372,19 @@ func (r *devResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
+	etag, diags := getPrivateETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Delete existing developer
-	err := r.client.DeleteDeveloper(state.ID.ValueString())
+	err := r.client.DeleteDeveloper(ctx, state.ID.ValueString(), etag)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(&resp.Diagnostics,
 			"Error Deleting Developer",
-			"Could not delete developer, unexpected error: "+err.Error(),
+			"Could not delete developer, unexpected error: ",
+			err,
 		)
 		return
 	}
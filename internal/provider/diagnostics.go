@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+)
+
+// addAPIErrorDiagnostics reports err from the DevOps API client. Field
+// validation errors for any of the given top-level schema attributes are
+// reported against that attribute; everything else becomes a general
+// error made of detail followed by the error text.
+func addAPIErrorDiagnostics(diags *diag.Diagnostics, summary, detail string, err error, attributes ...string) {
+	switch {
+	case errors.Is(err, client.ErrUnauthorized):
+		diags.AddError(summary, detail+err.Error()+"\n\n"+
+			"The DevOps API did not accept the provider credentials. "+
+			"Check the token or username and password in the provider configuration, "+
+			"or the DEVOPS_TOKEN, DEVOPS_USERNAME and DEVOPS_PASSWORD environment variables.")
+		return
+	case errors.Is(err, client.ErrPreconditionFailed):
+		diags.AddError(summary, detail+err.Error()+"\n\n"+
+			"The object was modified outside Terraform since it was last read. "+
+			"Run terraform refresh (or terraform apply -refresh-only) and re-plan before applying again.")
+		return
+	case errors.Is(err, client.ErrCircuitOpen):
+		diags.AddError(summary, detail+err.Error()+"\n\n"+
+			"The provider stopped contacting the DevOps API after repeated failures so the run does not wait on every resource. "+
+			"Check that the API is up, then run Terraform again.")
+		return
+	case errors.Is(err, client.ErrResponseTooLarge):
+		diags.AddError(summary, detail+err.Error()+"\n\n"+
+			"The DevOps API returned more data than the provider accepts in a single response. "+
+			"If the response is expected to be this large, raise max_response_bytes in the provider configuration, "+
+			"or set page_size so collections are fetched in smaller pages.")
+		return
+	case errors.Is(err, client.ErrForbidden):
+		diags.AddError(summary, detail+err.Error()+"\n\n"+
+			"The DevOps API accepted the provider credentials but denied access to this operation. "+
+			"Ensure the configured account has permission to manage this object.")
+		return
+	}
+
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, client.ErrValidation) || len(apiErr.FieldErrors) == 0 {
+		diags.AddError(summary, detail+err.Error())
+		return
+	}
+
+	unmatched := 0
+	for _, fieldErr := range apiErr.FieldErrors {
+		attribute := fieldErr.Field
+		if i := strings.IndexAny(attribute, ".["); i >= 0 {
+			attribute = attribute[:i]
+		}
+
+		if !containsString(attributes, attribute) {
+			unmatched++
+			continue
+		}
+
+		diags.AddAttributeError(path.Root(attribute), summary, "The DevOps API rejected this value: "+fieldErr.Message)
+	}
+
+	if unmatched > 0 {
+		diags.AddError(summary, detail+err.Error())
+	}
+}
+
+// addHealthCheckDiagnostics reports a failed pre-flight check of the API at
+// endpoints as a single error on the attribute that configured them. The
+// endpoints come from the DEVOPS_ENDPOINT environment variable when neither
+// attribute is set, so the error is not tied to an attribute then.
+func addHealthCheckDiagnostics(diags *diag.Diagnostics, config DevOpsProviderModel, endpoints []string, err error) {
+	var hint string
+	switch {
+	case errors.Is(err, client.ErrUnauthorized), errors.Is(err, client.ErrForbidden):
+		hint = "The DevOps API did not accept the provider credentials. " +
+			"Check the token or username and password in the provider configuration, " +
+			"or the DEVOPS_TOKEN, DEVOPS_USERNAME and DEVOPS_PASSWORD environment variables."
+	case errors.Is(err, client.ErrIncompatibleAPI):
+		hint = "The endpoint answered, but not like a DevOps API. " +
+			"Check that the endpoint, including any path prefix such as /api/v1, points at the DevOps API."
+	default:
+		hint = "Check that the endpoint is correct and that the DevOps API is running and reachable from this machine."
+	}
+
+	target := "the DevOps API at " + endpoints[0]
+	if len(endpoints) > 1 {
+		target = "the DevOps API at any of its endpoints " + strings.Join(endpoints, ", ")
+	}
+
+	if config.Endpoints.IsNull() && config.Endpoint.IsNull() {
+		target += " from the DEVOPS_ENDPOINT environment variable"
+	}
+
+	summary := "Unable to Reach DevOps API"
+	detail := "The provider could not verify " + target + ": " + err.Error() + "\n\n" + hint + "\n\n" +
+		"Set skip_health_check = true in the provider configuration to skip this check."
+
+	switch {
+	case !config.Endpoints.IsNull():
+		diags.AddAttributeError(path.Root("endpoints"), summary, detail)
+	case !config.Endpoint.IsNull():
+		diags.AddAttributeError(path.Root("endpoint"), summary, detail)
+	default:
+		diags.AddError(summary, detail)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -63,6 +63,9 @@ func (r *engineerResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *engineerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "devops-bootcamp_engineer Create")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Retrieve values from plan
 	var plan engineerResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -78,11 +81,12 @@ func (r *engineerResource) Create(ctx context.Context, req resource.CreateReques
 	}
 
 	// Create engineer via API
-	createdEngineer, err := r.client.CreateEngineer(engineer)
+	createdEngineer, err := r.client.CreateEngineer(ctx, engineer)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(&resp.Diagnostics,
 			"Error Creating Engineer",
-			"Could not create engineer, unexpected error: "+err.Error(),
+			"Could not create engineer, unexpected error: ",
+			err, "name", "email",
 		)
 		return
 	}
@@ -98,10 +102,16 @@ func (r *engineerResource) Create(ctx context.Context, req resource.CreateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// Remember the ETag so later updates and deletes are conditional
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, createdEngineer.ETag)...)
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (r *engineerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "devops-bootcamp_engineer Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Get current state
 	var state engineerResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -111,7 +121,7 @@ func (r *engineerResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 
 	// Get refreshed engineer value from API
-	engineer, err := r.client.GetEngineer(state.ID.ValueString())
+	engineer, err := r.client.GetEngineer(ctx, state.ID.ValueString())
 	if err != nil {
 		if errors.Is(err, client.ErrNotFound) {
 			resp.State.RemoveResource(ctx)
@@ -120,9 +130,10 @@ func (r *engineerResource) Read(ctx context.Context, req resource.ReadRequest, r
 				fmt.Sprintf("Engineer with ID %s no longer exists. Removing from state.", state.ID.ValueString()),
 			)
 		} else {
-			resp.Diagnostics.AddError(
+			addAPIErrorDiagnostics(&resp.Diagnostics,
 				"Error Reading Engineer",
-				"Could not read engineer ID "+state.ID.ValueString()+": "+err.Error(),
+				"Could not read engineer ID "+state.ID.ValueString()+": ",
+				err,
 			)
 		}
 		return
@@ -139,10 +150,16 @@ func (r *engineerResource) Read(ctx context.Context, req resource.ReadRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// Track the ETag of the refreshed engineer
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, engineer.ETag)...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *engineerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "devops-bootcamp_engineer Update")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Retrieve values from plan
 	var plan engineerResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -158,12 +175,41 @@ func (r *engineerResource) Update(ctx context.Context, req resource.UpdateReques
 		Email: plan.Email.ValueString(),
 	}
 
-	// Update engineer via API
-	updatedEngineer, err := r.client.UpdateEngineer(plan.ID.ValueString(), engineer)
+	// Only apply the update if nobody changed the engineer since the last read
+	etag, diags := getPrivateETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	engineer.ETag = etag
+
+	// Get current state to send only the changed fields
+	var state engineerResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	patch := client.EngineerPatch{ETag: etag}
+	if !plan.Name.Equal(state.Name) {
+		patch.Name = plan.Name.ValueStringPointer()
+	}
+	if !plan.Email.Equal(state.Email) {
+		patch.Email = plan.Email.ValueStringPointer()
+	}
+
+	// Update engineer via API, falling back to a full update when the
+	// API does not support partial updates
+	updatedEngineer, err := r.client.PatchEngineer(ctx, plan.ID.ValueString(), patch)
+	if errors.Is(err, client.ErrPatchNotSupported) {
+		updatedEngineer, err = r.client.UpdateEngineer(ctx, plan.ID.ValueString(), engineer)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(&resp.Diagnostics,
 			"Error Updating Engineer",
-			"Could not update engineer, unexpected error: "+err.Error(),
+			"Could not update engineer, unexpected error: ",
+			err, "name", "email",
 		)
 		return
 	}
@@ -178,10 +224,16 @@ func (r *engineerResource) Update(ctx context.Context, req resource.UpdateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// Track the ETag of the updated engineer
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, updatedEngineer.ETag)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *engineerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startSpan(ctx, "devops-bootcamp_engineer Delete")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Retrieve values from state
 	var state engineerResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -190,12 +242,19 @@ func (r *engineerResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	etag, diags := getPrivateETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Delete existing engineer
-	err := r.client.DeleteEngineer(state.ID.ValueString())
+	err := r.client.DeleteEngineer(ctx, state.ID.ValueString(), etag)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(&resp.Diagnostics,
 			"Error Deleting Engineer",
-			"Could not delete engineer, unexpected error: "+err.Error(),
+			"Could not delete engineer, unexpected error: ",
+			err,
 		)
 		return
 	}
@@ -58,14 +58,18 @@ func (d *engineersDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 
 // Read refreshes the Terraform state with the latest data.
 func (d *engineersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := startSpan(ctx, "data.devops-bootcamp_engineers Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	// Get engineers from the API
-	engineers, err := d.client.GetEngineers()
+	engineers, err := d.client.GetEngineers(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(&resp.Diagnostics,
 			"Unable to Read DevOps Engineers",
 			"An unexpected error occurred when reading the DevOps engineers. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
-				"DevOps Client Error: "+err.Error(),
+				"DevOps Client Error: ",
+			err,
 		)
 		return
 	}
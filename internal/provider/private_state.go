@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// privateKeyETag is the private state key holding the ETag of the API
+// object a resource manages.
+const privateKeyETag = "etag"
+
+// privateStateGetter is satisfied by the Private field of resource
+// requests.
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// privateStateSetter is satisfied by the Private field of resource
+// responses.
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// getPrivateETag returns the ETag recorded in private state, or an empty
+// string if there is none.
+func getPrivateETag(ctx context.Context, private privateStateGetter) (string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privateKeyETag)
+	if diags.HasError() || len(value) == 0 {
+		return "", diags
+	}
+
+	var etag string
+	if err := json.Unmarshal(value, &etag); err != nil {
+		diags.AddError(
+			"Invalid Private State",
+			"Could not decode the stored ETag, please report this issue to the provider developers: "+err.Error(),
+		)
+	}
+
+	return etag, diags
+}
+
+// setPrivateETag records etag in private state. An empty etag removes any
+// previously stored value so later writes are sent unconditionally. Reads
+// that cannot confirm the version, such as ones served from the collection
+// cache, pass an empty etag on purpose: keeping the old one would make
+// every later write fail on an ETag that no refresh would ever replace.
+func setPrivateETag(ctx context.Context, private privateStateSetter, etag string) diag.Diagnostics {
+	if etag == "" {
+		return private.SetKey(ctx, privateKeyETag, nil)
+	}
+
+	value, err := json.Marshal(etag)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Invalid Private State", "Could not encode the ETag: "+err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, privateKeyETag, value)
+}
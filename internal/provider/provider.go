@@ -5,9 +5,14 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -15,6 +20,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/madisonewebb/DOB-tf-providers/internal/client"
 )
@@ -30,11 +36,50 @@ type DevOpsProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// transport, when set, carries all API requests. Tests can set it to
+	// a cassette.Recorder to record and replay API traffic.
+	transport http.RoundTripper
 }
 
 // DevOpsProviderModel describes the provider data model.
 type DevOpsProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
+	Endpoint     types.String `tfsdk:"endpoint"`
+	Endpoints    types.List   `tfsdk:"endpoints"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin types.String `tfsdk:"retry_wait_min"`
+	RetryWaitMax types.String `tfsdk:"retry_wait_max"`
+	Token        types.String `tfsdk:"token"`
+	Username     types.String `tfsdk:"username"`
+	Password     types.String `tfsdk:"password"`
+
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCertPEM      types.String `tfsdk:"client_cert_pem"`
+	ClientCertFile     types.String `tfsdk:"client_cert_file"`
+	ClientKeyPEM       types.String `tfsdk:"client_key_pem"`
+	ClientKeyFile      types.String `tfsdk:"client_key_file"`
+	TLSServerName      types.String `tfsdk:"tls_server_name"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+
+	PageSize         types.Int64 `tfsdk:"page_size"`
+	MaxResponseBytes types.Int64 `tfsdk:"max_response_bytes"`
+
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+
+	Headers types.Map `tfsdk:"headers"`
+
+	SkipHealthCheck  types.Bool   `tfsdk:"skip_health_check"`
+	EndpointCooldown types.String `tfsdk:"endpoint_cooldown"`
+
+	CircuitBreakerThreshold types.Int64  `tfsdk:"circuit_breaker_threshold"`
+	CircuitBreakerCooldown  types.String `tfsdk:"circuit_breaker_cooldown"`
+
+	MetricsFile types.String `tfsdk:"metrics_file"`
+
+	TracingEndpoint types.String `tfsdk:"tracing_endpoint"`
+	TracingFile     types.String `tfsdk:"tracing_file"`
 }
 
 func (p *DevOpsProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -43,13 +88,128 @@ func (p *DevOpsProvider) Metadata(ctx context.Context, req provider.MetadataRequ
 }
 
 // Schema defines the provider-level schema for configuration data.
-// Note: DevOps API does not require authentication
+// Authentication is optional; deployments without auth leave token,
+// username and password unset.
 func (p *DevOpsProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
 				Optional:    true,
-				Description: "URI for DevOps API. May also be provided via DEVOPS_ENDPOINT environment variable.",
+				Description: "URI for DevOps API, optionally including a path prefix such as https://devops.example.com/api/v1. May also be provided via DEVOPS_ENDPOINT environment variable. Conflicts with endpoints.",
+			},
+			"endpoints": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "URIs of several DevOps API deployments, such as one per region, tried in order. Requests fail over to the next endpoint on connection errors or server errors. May also be provided as a comma-separated DEVOPS_ENDPOINT environment variable. Conflicts with endpoint.",
+			},
+			"endpoint_cooldown": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long an endpoint that failed is skipped in favour of the other endpoints, as a duration string such as \"30s\". Defaults to 30s.",
+			},
+			"circuit_breaker_threshold": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of consecutive requests that fail with a connection error or server error even after retries, after which the provider stops contacting the DevOps API for circuit_breaker_cooldown and fails fast instead. Set to 0 to disable the circuit breaker. Defaults to 5.",
+			},
+			"circuit_breaker_cooldown": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long requests fail fast once the circuit breaker opens, as a duration string such as \"30s\". A single request then checks whether the API recovered. Defaults to 30s.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of times a failed request is retried. Set to 0 to disable retries. Defaults to 3.",
+			},
+			"retry_wait_min": schema.StringAttribute{
+				Optional:    true,
+				Description: "Minimum time to wait between retries, as a duration string such as \"500ms\" or \"1s\". Defaults to 1s.",
+			},
+			"retry_wait_max": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum time to wait between retries, including any Retry-After delay requested by the API. Defaults to 30s.",
+			},
+			"token": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Bearer token for the DevOps API. May also be provided via DEVOPS_TOKEN environment variable. Conflicts with username and password.",
+			},
+			"username": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Username for HTTP basic authentication against the DevOps API. May also be provided via DEVOPS_USERNAME environment variable.",
+			},
+			"password": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password for HTTP basic authentication against the DevOps API. May also be provided via DEVOPS_PASSWORD environment variable.",
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded CA certificates trusted in addition to the system pool when verifying the DevOps API. Conflicts with ca_cert_file.",
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM encoded CA bundle trusted in addition to the system pool. Conflicts with ca_cert_pem.",
+			},
+			"client_cert_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded client certificate for mutual TLS. Requires client_key_pem or client_key_file. Conflicts with client_cert_file.",
+			},
+			"client_cert_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM encoded client certificate for mutual TLS. Conflicts with client_cert_pem.",
+			},
+			"client_key_pem": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM encoded private key for the client certificate. Conflicts with client_key_file.",
+			},
+			"client_key_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to the PEM encoded private key for the client certificate. Conflicts with client_key_pem.",
+			},
+			"tls_server_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Server name used to verify the DevOps API certificate, when it differs from the endpoint host.",
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Skip verification of the DevOps API server certificate. Intended for testing only.",
+			},
+			"page_size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of items to request per page when reading paginated collections. Defaults to the server's page size.",
+			},
+			"max_response_bytes": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum size in bytes of a single response from the DevOps API, after decompression. Larger responses fail with an error instead of exhausting memory. Defaults to 33554432 (32 MiB).",
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional:    true,
+				Description: "Maximum rate of requests sent to the DevOps API, shared by all resources and data sources of this provider. Unlimited by default.",
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of requests in flight to the DevOps API at once, shared by all resources and data sources of this provider. Unlimited by default.",
+			},
+			"headers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Additional HTTP headers, such as tenant IDs or routing hints, sent with every request to the DevOps API. " + reservedHeaderNames() + " are set by the provider and cannot be overridden.",
+			},
+			"metrics_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path of a file to write request counts, retries and latencies of DevOps API calls to, in the Prometheus text exposition format, when the provider process exits. Suitable for the node exporter textfile collector. Terraform starts a provider process for each walk of the configuration, such as the plan and apply walks of terraform apply, and each process adds its counts to those already in the file, so the counters grow across walks and commands. Delete the file to start counting from zero.",
+			},
+			"tracing_endpoint": schema.StringAttribute{
+				Optional:    true,
+				Description: "OTLP/HTTP endpoint URL, such as \"http://localhost:4318/v1/traces\", to export OpenTelemetry traces of provider operations and DevOps API calls to. Without it, tracing follows the standard OTEL_* environment variables, such as OTEL_EXPORTER_OTLP_ENDPOINT. Requests to the API carry W3C traceparent headers so its spans join the same trace.",
+			},
+			"tracing_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path of a file to append OpenTelemetry traces to as JSON, for use without a collector. Can be combined with tracing_endpoint.",
+			},
+			"skip_health_check": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Skip checking that the DevOps API is reachable when the provider is configured. The check also discovers which optional API features the server supports. Defaults to false.",
 			},
 		},
 	}
@@ -76,6 +236,33 @@ func (p *DevOpsProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		)
 	}
 
+	if config.Token.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Unknown DevOps API Token",
+			"The provider cannot create the DevOps API client as there is an unknown configuration value for the DevOps API token. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the DEVOPS_TOKEN environment variable.",
+		)
+	}
+
+	if config.Username.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("username"),
+			"Unknown DevOps API Username",
+			"The provider cannot create the DevOps API client as there is an unknown configuration value for the DevOps API username. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the DEVOPS_USERNAME environment variable.",
+		)
+	}
+
+	if config.Password.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password"),
+			"Unknown DevOps API Password",
+			"The provider cannot create the DevOps API client as there is an unknown configuration value for the DevOps API password. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the DEVOPS_PASSWORD environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -83,22 +270,42 @@ func (p *DevOpsProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	// Default values to environment variables, but override
 	// with Terraform configuration value if set.
 
-	endpoint := os.Getenv("DEVOPS_ENDPOINT")
+	token := os.Getenv("DEVOPS_TOKEN")
+	username := os.Getenv("DEVOPS_USERNAME")
+	password := os.Getenv("DEVOPS_PASSWORD")
+
+	if !config.Token.IsNull() {
+		token = config.Token.ValueString()
+	}
+
+	if !config.Username.IsNull() {
+		username = config.Username.ValueString()
+	}
 
-	if !config.Endpoint.IsNull() {
-		endpoint = config.Endpoint.ValueString()
+	if !config.Password.IsNull() {
+		password = config.Password.ValueString()
 	}
 
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
-	if endpoint == "" {
+	endpoints := endpointsFromConfig(ctx, config, &resp.Diagnostics)
+
+	if token != "" && (username != "" || password != "") {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("endpoint"),
-			"Missing DevOps API Endpoint",
-			"The provider cannot create the DevOps API client as there is a missing or empty value for the DevOps API endpoint. "+
-				"Set the endpoint value in the configuration or use the DEVOPS_ENDPOINT environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+			path.Root("token"),
+			"Conflicting DevOps API Credentials",
+			"The provider cannot create the DevOps API client as both a token and basic authentication credentials are configured. "+
+				"Set either token (or DEVOPS_TOKEN) or username and password (or DEVOPS_USERNAME and DEVOPS_PASSWORD), not both.",
+		)
+	}
+
+	if (username == "") != (password == "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("username"),
+			"Incomplete DevOps API Credentials",
+			"The provider cannot create the DevOps API client as only one of username and password is set. "+
+				"Basic authentication requires both values, either in the configuration or via the DEVOPS_USERNAME and DEVOPS_PASSWORD environment variables.",
 		)
 	}
 
@@ -106,8 +313,131 @@ func (p *DevOpsProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	retryPolicy := retryPolicyFromConfig(config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tlsConfig := tlsConfigFromConfig(config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	headers := headersFromConfig(ctx, config, &resp.Diagnostics)
+	cooldown := endpointCooldownFromConfig(config, &resp.Diagnostics)
+	breakerThreshold, breakerCooldown := circuitBreakerFromConfig(config, &resp.Diagnostics)
+	tracingSettings, tracingEnabled := tracingFromConfig(config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if tracingEnabled {
+		if err := setupTracing(ctx, p.version, tracingSettings); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Set Up Tracing",
+				"An unexpected error occurred when setting up OpenTelemetry tracing. "+
+					"Check tracing_endpoint, tracing_file and the OTEL_* environment variables.\n\n"+
+					"Tracing Error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	ctx, span := startSpan(ctx, "devops-bootcamp Configure")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
+	opts := []client.Option{
+		client.WithRetryPolicy(retryPolicy),
+		client.WithEndpointCooldown(cooldown),
+		client.WithCircuitBreaker(breakerThreshold, breakerCooldown),
+		client.WithUserAgent(userAgent(p.version, req.TerraformVersion)),
+	}
+
+	if len(headers) > 0 {
+		opts = append(opts, client.WithHeaders(headers))
+	}
+
+	if tlsConfig != nil {
+		opts = append(opts, client.WithTLSConfig(tlsConfig))
+	}
+
+	if !config.PageSize.IsNull() && !config.PageSize.IsUnknown() {
+		if config.PageSize.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("page_size"),
+				"Invalid Page Size",
+				"The page_size value must be at least 1.",
+			)
+			return
+		}
+		opts = append(opts, client.WithPageSize(int(config.PageSize.ValueInt64())))
+	}
+
+	if !config.MaxResponseBytes.IsNull() && !config.MaxResponseBytes.IsUnknown() {
+		if config.MaxResponseBytes.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_response_bytes"),
+				"Invalid Max Response Bytes",
+				"The max_response_bytes value must be at least 1.",
+			)
+			return
+		}
+		opts = append(opts, client.WithMaxResponseBytes(config.MaxResponseBytes.ValueInt64()))
+	}
+
+	if !config.RequestsPerSecond.IsNull() && !config.RequestsPerSecond.IsUnknown() {
+		if config.RequestsPerSecond.ValueFloat64() <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("requests_per_second"),
+				"Invalid Requests Per Second",
+				"The requests_per_second value must be greater than zero.",
+			)
+			return
+		}
+		opts = append(opts, client.WithRateLimit(config.RequestsPerSecond.ValueFloat64()))
+	}
+
+	if !config.MaxConcurrentRequests.IsNull() && !config.MaxConcurrentRequests.IsUnknown() {
+		if config.MaxConcurrentRequests.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_concurrent_requests"),
+				"Invalid Max Concurrent Requests",
+				"The max_concurrent_requests value must be at least 1.",
+			)
+			return
+		}
+		opts = append(opts, client.WithMaxConcurrentRequests(int(config.MaxConcurrentRequests.ValueInt64())))
+	}
+
+	if !config.MetricsFile.IsNull() && !config.MetricsFile.IsUnknown() {
+		if config.MetricsFile.ValueString() == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("metrics_file"),
+				"Invalid Metrics File",
+				"The metrics_file value must not be empty.",
+			)
+			return
+		}
+		opts = append(opts, client.WithMetrics(metricsFor(config.MetricsFile.ValueString())))
+	}
+
+	switch {
+	case token != "":
+		opts = append(opts, client.WithToken(token))
+	case username != "":
+		opts = append(opts, client.WithBasicAuth(username, password))
+	}
+
+	if len(endpoints) > 1 {
+		opts = append(opts, client.WithEndpoints(endpoints[1:]...))
+	}
+
+	if p.transport != nil {
+		opts = append(opts, client.WithTransport(p.transport))
+	}
+
 	// Create a new DevOps client using the configuration values
-	apiClient, err := client.NewClient(endpoint)
+	apiClient, err := client.NewClient(endpoints[0], opts...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create DevOps API Client",
@@ -118,12 +448,106 @@ func (p *DevOpsProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	if !config.SkipHealthCheck.ValueBool() {
+		capabilities, err := apiClient.Probe(ctx)
+		if err != nil {
+			addHealthCheckDiagnostics(&resp.Diagnostics, config, endpoints, err)
+			return
+		}
+
+		tflog.Debug(ctx, "Discovered DevOps API capabilities", map[string]interface{}{
+			"api_version":      capabilities.APIVersion,
+			"patch":            capabilities.Patch.String(),
+			"single_item_get":  capabilities.SingleItemGet.String(),
+			"idempotency_keys": capabilities.IdempotencyKeys.String(),
+		})
+	}
+
 	// Make the DevOps client available during DataSource and Resource
 	// type Configure methods.
 	resp.DataSourceData = apiClient
 	resp.ResourceData = apiClient
 }
 
+// circuitBreakerFromConfig returns the circuit breaker threshold and
+// cooldown, starting from the client defaults.
+func circuitBreakerFromConfig(config DevOpsProviderModel, diags *diag.Diagnostics) (int, time.Duration) {
+	threshold := client.DefaultBreakerThreshold
+	cooldown := client.DefaultBreakerCooldown
+
+	if !config.CircuitBreakerThreshold.IsNull() && !config.CircuitBreakerThreshold.IsUnknown() {
+		if config.CircuitBreakerThreshold.ValueInt64() < 0 {
+			diags.AddAttributeError(
+				path.Root("circuit_breaker_threshold"),
+				"Invalid Circuit Breaker Threshold",
+				"The circuit_breaker_threshold value must be zero or greater.",
+			)
+		}
+		threshold = int(config.CircuitBreakerThreshold.ValueInt64())
+	}
+
+	if !config.CircuitBreakerCooldown.IsNull() && !config.CircuitBreakerCooldown.IsUnknown() {
+		parsed, err := time.ParseDuration(config.CircuitBreakerCooldown.ValueString())
+		if err != nil || parsed <= 0 {
+			diags.AddAttributeError(
+				path.Root("circuit_breaker_cooldown"),
+				"Invalid Circuit Breaker Cooldown",
+				fmt.Sprintf("The circuit_breaker_cooldown value %q must be a positive duration such as \"30s\" or \"2m\".", config.CircuitBreakerCooldown.ValueString()),
+			)
+		} else {
+			cooldown = parsed
+		}
+	}
+
+	return threshold, cooldown
+}
+
+// retryPolicyFromConfig builds the client retry policy, starting from the
+// client defaults and applying any values set in the provider configuration.
+func retryPolicyFromConfig(config DevOpsProviderModel, diags *diag.Diagnostics) client.RetryPolicy {
+	policy := client.DefaultRetryPolicy()
+
+	if !config.MaxRetries.IsNull() && !config.MaxRetries.IsUnknown() {
+		if config.MaxRetries.ValueInt64() < 0 {
+			diags.AddAttributeError(
+				path.Root("max_retries"),
+				"Invalid Max Retries",
+				"The max_retries value must be zero or greater.",
+			)
+		}
+		policy.MaxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	parseWait := func(value types.String, attribute string, target *time.Duration) {
+		if value.IsNull() || value.IsUnknown() {
+			return
+		}
+		wait, err := time.ParseDuration(value.ValueString())
+		if err != nil || wait < 0 {
+			diags.AddAttributeError(
+				path.Root(attribute),
+				"Invalid Retry Wait Duration",
+				fmt.Sprintf("The %s value %q must be a non-negative duration such as \"500ms\" or \"2s\".", attribute, value.ValueString()),
+			)
+			return
+		}
+		*target = wait
+	}
+
+	parseWait(config.RetryWaitMin, "retry_wait_min", &policy.WaitMin)
+	parseWait(config.RetryWaitMax, "retry_wait_max", &policy.WaitMax)
+
+	if policy.WaitMin > policy.WaitMax {
+		diags.AddAttributeError(
+			path.Root("retry_wait_min"),
+			"Invalid Retry Wait Duration",
+			fmt.Sprintf("The retry_wait_min value (%s) must not be greater than retry_wait_max (%s).", policy.WaitMin, policy.WaitMax),
+		)
+	}
+
+	return policy
+}
+
 func (p *DevOpsProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewEngineerResource,
@@ -146,6 +570,13 @@ func (p *DevOpsProvider) Functions(ctx context.Context) []func() function.Functi
 	return []func() function.Function{}
 }
 
+// Shutdown writes the metrics_file and exports the remaining trace spans
+// of this provider process. main calls it once Terraform is done with the
+// provider.
+func Shutdown(ctx context.Context) error {
+	return errors.Join(writeMetrics(), shutdownTracing(ctx))
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &DevOpsProvider{
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+)
+
+// endpointsFromConfig returns the DevOps API endpoints in failover order.
+// They come from endpoints, endpoint, or the comma-separated
+// DEVOPS_ENDPOINT environment variable, in that order of precedence.
+func endpointsFromConfig(ctx context.Context, config DevOpsProviderModel, diags *diag.Diagnostics) []string {
+	if config.Endpoints.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("endpoints"),
+			"Unknown DevOps API Endpoints",
+			"The provider cannot create the DevOps API client as there is an unknown configuration value for the DevOps API endpoints. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the DEVOPS_ENDPOINT environment variable.",
+		)
+		return nil
+	}
+
+	if !config.Endpoint.IsNull() && !config.Endpoints.IsNull() {
+		diags.AddAttributeError(
+			path.Root("endpoints"),
+			"Conflicting DevOps API Endpoints",
+			"The provider cannot create the DevOps API client as both endpoint and endpoints are configured. "+
+				"Set endpoint for a single DevOps API, or endpoints to fail over between several.",
+		)
+		return nil
+	}
+
+	var endpoints []string
+	attributePath := func(int) path.Path { return path.Root("endpoint") }
+
+	switch {
+	case !config.Endpoints.IsNull():
+		var values []types.String
+		diags.Append(config.Endpoints.ElementsAs(ctx, &values, false)...)
+		if diags.HasError() {
+			return nil
+		}
+		for i, value := range values {
+			if value.IsUnknown() {
+				diags.AddAttributeError(
+					path.Root("endpoints").AtListIndex(i),
+					"Unknown DevOps API Endpoint",
+					"The provider cannot create the DevOps API client as there is an unknown value in the DevOps API endpoints. "+
+						"Either target apply the source of the value first or set the value statically in the configuration.",
+				)
+				continue
+			}
+			endpoints = append(endpoints, value.ValueString())
+		}
+		attributePath = func(i int) path.Path { return path.Root("endpoints").AtListIndex(i) }
+	case !config.Endpoint.IsNull():
+		endpoints = []string{config.Endpoint.ValueString()}
+	default:
+		for _, endpoint := range strings.Split(os.Getenv("DEVOPS_ENDPOINT"), ",") {
+			if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	if diags.HasError() {
+		return nil
+	}
+
+	// If the expected configuration is missing, return errors with
+	// provider-specific guidance.
+
+	if len(endpoints) == 0 || (len(endpoints) == 1 && endpoints[0] == "") {
+		diags.AddAttributeError(
+			path.Root("endpoint"),
+			"Missing DevOps API Endpoint",
+			"The provider cannot create the DevOps API client as there is a missing or empty value for the DevOps API endpoint. "+
+				"Set the endpoint or endpoints value in the configuration or use the DEVOPS_ENDPOINT environment variable. "+
+				"If either is already set, ensure the value is not empty.",
+		)
+		return nil
+	}
+
+	for i, endpoint := range endpoints {
+		if _, err := client.ParseEndpoint(endpoint); err != nil {
+			diags.AddAttributeError(
+				attributePath(i),
+				"Invalid DevOps API Endpoint",
+				fmt.Sprintf("The provider cannot create the DevOps API client as the endpoint %q is not valid: %s. ", endpoint, err)+
+					"Set the endpoint to an http or https URL with a host and an optional path prefix, such as https://devops.example.com/api/v1.",
+			)
+		}
+	}
+	if diags.HasError() {
+		return nil
+	}
+
+	return endpoints
+}
+
+// endpointCooldownFromConfig returns how long a failed endpoint is skipped,
+// defaulting to the client's default.
+func endpointCooldownFromConfig(config DevOpsProviderModel, diags *diag.Diagnostics) time.Duration {
+	if config.EndpointCooldown.IsNull() || config.EndpointCooldown.IsUnknown() {
+		return client.DefaultEndpointCooldown
+	}
+
+	cooldown, err := time.ParseDuration(config.EndpointCooldown.ValueString())
+	if err != nil || cooldown < 0 {
+		diags.AddAttributeError(
+			path.Root("endpoint_cooldown"),
+			"Invalid Endpoint Cooldown",
+			fmt.Sprintf("The endpoint_cooldown value %q must be a non-negative duration such as \"30s\" or \"2m\".", config.EndpointCooldown.ValueString()),
+		)
+		return client.DefaultEndpointCooldown
+	}
+
+	return cooldown
+}
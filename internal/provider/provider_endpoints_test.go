@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func endpointList(values ...string) types.List {
+	elements := make([]attr.Value, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, types.StringValue(value))
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+func TestEndpointsFromConfig(t *testing.T) {
+	testCases := map[string]struct {
+		env       string
+		config    DevOpsProviderModel
+		expected  []string
+		errorPath *path.Path
+	}{
+		"endpoint": {
+			config:   DevOpsProviderModel{Endpoint: types.StringValue("https://us.example.com")},
+			expected: []string{"https://us.example.com"},
+		},
+		"endpoints": {
+			config:   DevOpsProviderModel{Endpoints: endpointList("https://us.example.com", "https://eu.example.com/api")},
+			expected: []string{"https://us.example.com", "https://eu.example.com/api"},
+		},
+		"env": {
+			env:      "https://us.example.com, https://eu.example.com",
+			expected: []string{"https://us.example.com", "https://eu.example.com"},
+		},
+		"config-over-env": {
+			env:      "https://env.example.com",
+			config:   DevOpsProviderModel{Endpoint: types.StringValue("https://us.example.com")},
+			expected: []string{"https://us.example.com"},
+		},
+		"missing": {
+			errorPath: pathPointer(path.Root("endpoint")),
+		},
+		"conflict": {
+			config: DevOpsProviderModel{
+				Endpoint:  types.StringValue("https://us.example.com"),
+				Endpoints: endpointList("https://eu.example.com"),
+			},
+			errorPath: pathPointer(path.Root("endpoints")),
+		},
+		"invalid-endpoint": {
+			config:    DevOpsProviderModel{Endpoint: types.StringValue("us.example.com")},
+			errorPath: pathPointer(path.Root("endpoint")),
+		},
+		"invalid-list-item": {
+			config:    DevOpsProviderModel{Endpoints: endpointList("https://us.example.com", "ftp://eu.example.com")},
+			errorPath: pathPointer(path.Root("endpoints").AtListIndex(1)),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("DEVOPS_ENDPOINT", testCase.env)
+
+			var diags diag.Diagnostics
+			endpoints := endpointsFromConfig(context.Background(), testCase.config, &diags)
+
+			if testCase.errorPath == nil {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", diags)
+				}
+				if !slices.Equal(endpoints, testCase.expected) {
+					t.Errorf("expected %v, got %v", testCase.expected, endpoints)
+				}
+				return
+			}
+
+			for _, d := range diags.Errors() {
+				if withPath, ok := d.(diag.DiagnosticWithPath); ok && withPath.Path().Equal(*testCase.errorPath) {
+					return
+				}
+			}
+			t.Errorf("expected error at %s, got %v", testCase.errorPath, diags)
+		})
+	}
+}
+
+func TestHealthCheckDiagnostics(t *testing.T) {
+	testCases := map[string]struct {
+		config    DevOpsProviderModel
+		endpoints []string
+		errorPath *path.Path
+	}{
+		"endpoint": {
+			config:    DevOpsProviderModel{Endpoint: types.StringValue("https://us.example.com")},
+			endpoints: []string{"https://us.example.com"},
+			errorPath: pathPointer(path.Root("endpoint")),
+		},
+		"endpoints": {
+			config:    DevOpsProviderModel{Endpoints: endpointList("https://us.example.com", "https://eu.example.com")},
+			endpoints: []string{"https://us.example.com", "https://eu.example.com"},
+			errorPath: pathPointer(path.Root("endpoints")),
+		},
+		"env": {
+			endpoints: []string{"https://us.example.com", "https://eu.example.com"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addHealthCheckDiagnostics(&diags, testCase.config, testCase.endpoints, errors.New("connection refused"))
+
+			if len(diags) != 1 {
+				t.Fatalf("expected one diagnostic, got %v", diags)
+			}
+			withPath, ok := diags[0].(diag.DiagnosticWithPath)
+			switch {
+			case testCase.errorPath == nil && ok:
+				t.Errorf("expected no attribute path, got %s", withPath.Path())
+			case testCase.errorPath != nil && (!ok || !withPath.Path().Equal(*testCase.errorPath)):
+				t.Errorf("expected error at %s, got %v", testCase.errorPath, diags)
+			}
+
+			for _, endpoint := range testCase.endpoints {
+				if !strings.Contains(diags[0].Detail(), endpoint) {
+					t.Errorf("expected %s in the detail, got %q", endpoint, diags[0].Detail())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/net/http/httpguts"
+)
+
+// reservedHeaders are set by the provider itself and cannot be overridden
+// through the headers attribute.
+var reservedHeaders = map[string]string{
+	"Accept-Encoding": "The provider negotiates response compression itself.",
+	"Authorization":   "Use the token, username and password attributes to authenticate.",
+	"Content-Length":  "It is computed for each request.",
+	"Content-Type":    "It is set for each request.",
+	"Host":            "It is derived from the endpoint.",
+	"Idempotency-Key": "It is generated for each create.",
+	"If-Match":        "It is managed for conditional writes.",
+	"User-Agent":      "It identifies the provider and Terraform versions.",
+}
+
+// reservedHeaderNames lists the reserved headers for the schema
+// documentation, such as "Accept-Encoding, Authorization and User-Agent".
+func reservedHeaderNames() string {
+	names := make([]string, 0, len(reservedHeaders))
+	for name := range reservedHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// userAgent returns the User-Agent sent to the DevOps API, identifying the
+// provider version and, when known, the Terraform version.
+func userAgent(providerVersion, terraformVersion string) string {
+	userAgent := "terraform-provider-devops-bootcamp/" + providerVersion
+	if terraformVersion != "" {
+		userAgent += " Terraform/" + terraformVersion
+	}
+	return userAgent
+}
+
+// headersFromConfig validates the static headers to add to every request.
+// It returns nil when none are configured.
+func headersFromConfig(ctx context.Context, config DevOpsProviderModel, diags *diag.Diagnostics) map[string]string {
+	if config.Headers.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("headers"),
+			"Unknown DevOps API Headers",
+			"The provider cannot create the DevOps API client as there is an unknown configuration value for headers. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+		return nil
+	}
+	if config.Headers.IsNull() {
+		return nil
+	}
+
+	var values map[string]types.String
+	diags.Append(config.Headers.ElementsAs(ctx, &values, false)...)
+	if diags.HasError() {
+		return nil
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := make(map[string]string, len(values))
+	for _, name := range names {
+		value := values[name]
+		attributePath := path.Root("headers").AtMapKey(name)
+
+		switch {
+		case value.IsUnknown():
+			diags.AddAttributeError(
+				attributePath,
+				"Unknown DevOps API Header",
+				fmt.Sprintf("The provider cannot create the DevOps API client as the value of header %q is unknown. "+
+					"Either target apply the source of the value first or set the value statically in the configuration.", name),
+			)
+			continue
+		case !httpguts.ValidHeaderFieldName(name):
+			diags.AddAttributeError(
+				attributePath,
+				"Invalid DevOps API Header",
+				fmt.Sprintf("%q is not a valid HTTP header name.", name),
+			)
+			continue
+		case !httpguts.ValidHeaderFieldValue(value.ValueString()):
+			diags.AddAttributeError(
+				attributePath,
+				"Invalid DevOps API Header",
+				fmt.Sprintf("The value of header %q contains characters that are not allowed in HTTP headers.", name),
+			)
+			continue
+		}
+
+		if reason, reserved := reservedHeaders[http.CanonicalHeaderKey(name)]; reserved {
+			diags.AddAttributeError(
+				attributePath,
+				"Reserved DevOps API Header",
+				fmt.Sprintf("The %s header cannot be set through headers. %s", http.CanonicalHeaderKey(name), reason),
+			)
+			continue
+		}
+
+		headers[name] = value.ValueString()
+	}
+
+	if diags.HasError() {
+		return nil
+	}
+
+	return headers
+}
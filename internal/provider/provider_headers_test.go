@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestHeadersFromConfig(t *testing.T) {
+	headerMap := func(values map[string]attr.Value) types.Map {
+		return types.MapValueMust(types.StringType, values)
+	}
+
+	testCases := map[string]struct {
+		headers   types.Map
+		expected  map[string]string
+		errorPath *path.Path
+	}{
+		"unset": {
+			headers: types.MapNull(types.StringType),
+		},
+		"static": {
+			headers:  headerMap(map[string]attr.Value{"X-Tenant-ID": types.StringValue("acme")}),
+			expected: map[string]string{"X-Tenant-ID": "acme"},
+		},
+		"reserved": {
+			headers:   headerMap(map[string]attr.Value{"authorization": types.StringValue("Bearer x")}),
+			errorPath: pathPointer(path.Root("headers").AtMapKey("authorization")),
+		},
+		"idempotency-key": {
+			headers:   headerMap(map[string]attr.Value{"Idempotency-Key": types.StringValue("static")}),
+			errorPath: pathPointer(path.Root("headers").AtMapKey("Idempotency-Key")),
+		},
+		"invalid-name": {
+			headers:   headerMap(map[string]attr.Value{"X Tenant": types.StringValue("acme")}),
+			errorPath: pathPointer(path.Root("headers").AtMapKey("X Tenant")),
+		},
+		"invalid-value": {
+			headers:   headerMap(map[string]attr.Value{"X-Tenant": types.StringValue("acme\r\nX-Admin: true")}),
+			errorPath: pathPointer(path.Root("headers").AtMapKey("X-Tenant")),
+		},
+		"unknown-value": {
+			headers:   headerMap(map[string]attr.Value{"X-Tenant": types.StringUnknown()}),
+			errorPath: pathPointer(path.Root("headers").AtMapKey("X-Tenant")),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			headers := headersFromConfig(context.Background(), DevOpsProviderModel{Headers: testCase.headers}, &diags)
+
+			if testCase.errorPath == nil {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", diags)
+				}
+				if len(headers) != len(testCase.expected) {
+					t.Fatalf("expected %v, got %v", testCase.expected, headers)
+				}
+				for key, value := range testCase.expected {
+					if headers[key] != value {
+						t.Errorf("expected %s: %q, got %q", key, value, headers[key])
+					}
+				}
+				return
+			}
+
+			for _, d := range diags.Errors() {
+				if withPath, ok := d.(diag.DiagnosticWithPath); ok && withPath.Path().Equal(*testCase.errorPath) {
+					return
+				}
+			}
+			t.Errorf("expected error at %s, got %v", testCase.errorPath, diags)
+		})
+	}
+}
+
+func TestReservedHeaderNames(t *testing.T) {
+	expected := "Accept-Encoding, Authorization, Content-Length, Content-Type, Host, Idempotency-Key, If-Match and User-Agent"
+	if got := reservedHeaderNames(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	if got := userAgent("1.2.3", "1.9.0"); got != "terraform-provider-devops-bootcamp/1.2.3 Terraform/1.9.0" {
+		t.Errorf("unexpected User-Agent %q", got)
+	}
+	if got := userAgent("dev", ""); got != "terraform-provider-devops-bootcamp/dev" {
+		t.Errorf("unexpected User-Agent %q", got)
+	}
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+)
+
+// metricsFiles holds the client metrics of this provider process by the
+// metrics_file they are written to. Terraform may configure the provider
+// more than once per process, and configurations sharing a file share
+// their counts.
+var metricsFiles = struct {
+	mu    sync.Mutex
+	files map[string]*client.Metrics
+}{files: map[string]*client.Metrics{}}
+
+// metricsFor returns the metrics written to file, creating them on first
+// use.
+func metricsFor(file string) *client.Metrics {
+	metricsFiles.mu.Lock()
+	defer metricsFiles.mu.Unlock()
+
+	metrics, ok := metricsFiles.files[file]
+	if !ok {
+		metrics = client.NewMetrics()
+		metricsFiles.files[file] = metrics
+	}
+	return metrics
+}
+
+// writeMetrics writes the client metrics of every provider configuration
+// with a metrics_file.
+func writeMetrics() error {
+	metricsFiles.mu.Lock()
+	defer metricsFiles.mu.Unlock()
+
+	var errs []error
+	for file, metrics := range metricsFiles.files {
+		if err := metrics.WriteFile(file); err != nil {
+			errs = append(errs, fmt.Errorf("writing metrics to %s: %w", file, err))
+		}
+	}
+	return errors.Join(errs...)
+}
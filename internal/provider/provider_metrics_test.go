@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+	"github.com/madisonewebb/DOB-tf-providers/internal/fakeapi"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "devops.prom")
+	t.Cleanup(func() {
+		metricsFiles.mu.Lock()
+		defer metricsFiles.mu.Unlock()
+		delete(metricsFiles.files, file)
+	})
+
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	// Configurations sharing a file add up their requests.
+	for range 2 {
+		c, err := client.NewClient(server.URL, client.WithMetrics(metricsFor(file)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.GetEngineers(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if err := writeMetrics(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("expected the metrics file to be written: %s", err)
+	}
+	expected := `devops_api_requests_total{code="200",method="GET",route="/engineers"} 2`
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("expected %q in:\n%s", expected, data)
+	}
+}
@@ -1,23 +1,24 @@
 package provider
 
 import (
+	"fmt"
+	"os"
+	"testing"
+
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
-)
 
-const (
-	// providerConfig is a shared configuration to combine with the actual
-	// test configuration so the HashiCups client is properly configured.
-	// It is also possible to use the HASHICUPS_ environment variables instead,
-	// such as updating the Makefile and running the testing through that tool.
-	providerConfig = `
-provider "devops-bootcamp" {
-  endpoint = "http://localhost:8080"
-}
-`
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+	"github.com/madisonewebb/DOB-tf-providers/internal/fakeapi"
 )
 
 var (
+	// providerConfig is a shared configuration to combine with the actual
+	// test configuration so the DevOps client is properly configured. It
+	// points at an in-memory fake API started by TestMain, unless
+	// DEVOPS_ENDPOINT is set to run the tests against a real API instead.
+	providerConfig string
+
 	// testAccProtoV6ProviderFactories are used to instantiate a provider during
 	// acceptance testing. The factory function will be invoked for every Terraform
 	// CLI command executed to create a provider server to which the CLI can
@@ -26,3 +27,33 @@ var (
 		"devops-bootcamp": providerserver.NewProtocol6WithError(New("test")()),
 	}
 )
+
+func TestMain(m *testing.M) {
+	endpoint := os.Getenv("DEVOPS_ENDPOINT")
+
+	// A real API is configured through DEVOPS_ENDPOINT itself, which may
+	// list several comma-separated endpoints.
+	providerConfig = `
+provider "devops-bootcamp" {}
+`
+
+	var server *fakeapi.Server
+	if endpoint == "" {
+		server = fakeapi.NewServer()
+		server.AddEngineer(client.Engineer{Name: "Grace Hopper", Email: "grace.hopper@example.com"})
+
+		providerConfig = fmt.Sprintf(`
+provider "devops-bootcamp" {
+  endpoint = %q
+}
+`, server.URL)
+	}
+
+	code := m.Run()
+
+	if server != nil {
+		server.Close()
+	}
+
+	os.Exit(code)
+}
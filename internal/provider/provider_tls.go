@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// tlsConfigFromConfig builds the TLS settings for the DevOps API client.
+// It returns nil when no TLS attribute is set so the client keeps Go's
+// default transport.
+func tlsConfigFromConfig(config DevOpsProviderModel, diags *diag.Diagnostics) *tls.Config {
+	for attribute, value := range map[string]attr.Value{
+		"ca_cert_pem":          config.CACertPEM,
+		"ca_cert_file":         config.CACertFile,
+		"client_cert_pem":      config.ClientCertPEM,
+		"client_cert_file":     config.ClientCertFile,
+		"client_key_pem":       config.ClientKeyPEM,
+		"client_key_file":      config.ClientKeyFile,
+		"tls_server_name":      config.TLSServerName,
+		"insecure_skip_verify": config.InsecureSkipVerify,
+	} {
+		if value.IsUnknown() {
+			diags.AddAttributeError(
+				path.Root(attribute),
+				"Unknown DevOps API TLS Configuration",
+				fmt.Sprintf("The provider cannot create the DevOps API client as there is an unknown configuration value for %s. "+
+					"Either target apply the source of the value first or set the value statically in the configuration.", attribute),
+			)
+		}
+	}
+	if diags.HasError() {
+		return nil
+	}
+
+	caPEM := pemFromConfig(config.CACertPEM, "ca_cert_pem", config.CACertFile, "ca_cert_file", diags)
+	certPEM := pemFromConfig(config.ClientCertPEM, "client_cert_pem", config.ClientCertFile, "client_cert_file", diags)
+	keyPEM := pemFromConfig(config.ClientKeyPEM, "client_key_pem", config.ClientKeyFile, "client_key_file", diags)
+	if diags.HasError() {
+		return nil
+	}
+
+	serverName := config.TLSServerName.ValueString()
+	insecure := config.InsecureSkipVerify.ValueBool()
+
+	if caPEM == nil && certPEM == nil && keyPEM == nil && serverName == "" && !insecure {
+		return nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         serverName,
+		InsecureSkipVerify: insecure,
+	}
+
+	if insecure {
+		diags.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"DevOps API Certificate Verification Disabled",
+			"The provider will not verify the DevOps API server certificate. "+
+				"Only use insecure_skip_verify for testing; prefer ca_cert_pem or ca_cert_file for private certificate authorities.",
+		)
+	}
+
+	if caPEM != nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			diags.AddAttributeError(
+				caAttributePath(config),
+				"Invalid DevOps API CA Certificate",
+				"The provider could not find any valid PEM encoded certificate in the configured CA bundle. "+
+					"Ensure the value contains one or more \"-----BEGIN CERTIFICATE-----\" blocks.",
+			)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case certPEM != nil && keyPEM == nil:
+		diags.AddAttributeError(
+			path.Root("client_key_pem"),
+			"Missing DevOps API Client Key",
+			"A client certificate is configured without a private key. Set client_key_pem or client_key_file.",
+		)
+	case certPEM == nil && keyPEM != nil:
+		diags.AddAttributeError(
+			path.Root("client_cert_pem"),
+			"Missing DevOps API Client Certificate",
+			"A client private key is configured without a certificate. Set client_cert_pem or client_cert_file.",
+		)
+	case certPEM != nil:
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			diags.AddAttributeError(
+				clientCertAttributePath(config),
+				"Invalid DevOps API Client Certificate",
+				"The provider could not load the client certificate and private key: "+err.Error(),
+			)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if diags.HasError() {
+		return nil
+	}
+
+	return tlsConfig
+}
+
+// pemFromConfig returns PEM material set either inline or as a file path.
+// Setting both attributes is an error.
+func pemFromConfig(inline types.String, inlineAttribute string, file types.String, fileAttribute string, diags *diag.Diagnostics) []byte {
+	if !inline.IsNull() && !file.IsNull() {
+		diags.AddAttributeError(
+			path.Root(inlineAttribute),
+			"Conflicting DevOps API TLS Configuration",
+			fmt.Sprintf("Only one of %s and %s may be set.", inlineAttribute, fileAttribute),
+		)
+		return nil
+	}
+
+	if !inline.IsNull() {
+		return []byte(inline.ValueString())
+	}
+
+	if file.IsNull() {
+		return nil
+	}
+
+	data, err := os.ReadFile(file.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(fileAttribute),
+			"Unable to Read DevOps API TLS File",
+			fmt.Sprintf("The provider could not read %s: %s", file.ValueString(), err),
+		)
+		return nil
+	}
+
+	return data
+}
+
+func caAttributePath(config DevOpsProviderModel) path.Path {
+	if !config.CACertFile.IsNull() {
+		return path.Root("ca_cert_file")
+	}
+	return path.Root("ca_cert_pem")
+}
+
+func clientCertAttributePath(config DevOpsProviderModel) path.Path {
+	if !config.ClientCertFile.IsNull() {
+		return path.Root("client_cert_file")
+	}
+	return path.Root("client_cert_pem")
+}
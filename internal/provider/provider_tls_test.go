@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTLSConfigFromConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config    DevOpsProviderModel
+		expectNil bool
+		errorPath *path.Path
+	}{
+		"unset": {
+			expectNil: true,
+		},
+		"server-name": {
+			config: DevOpsProviderModel{TLSServerName: types.StringValue("api.internal")},
+		},
+		"invalid-ca": {
+			config:    DevOpsProviderModel{CACertPEM: types.StringValue("not a certificate")},
+			expectNil: true,
+			errorPath: pathPointer(path.Root("ca_cert_pem")),
+		},
+		"conflicting-ca": {
+			config: DevOpsProviderModel{
+				CACertPEM:  types.StringValue("x"),
+				CACertFile: types.StringValue("/tmp/ca.pem"),
+			},
+			expectNil: true,
+			errorPath: pathPointer(path.Root("ca_cert_pem")),
+		},
+		"cert-without-key": {
+			config:    DevOpsProviderModel{ClientCertPEM: types.StringValue("x")},
+			expectNil: true,
+			errorPath: pathPointer(path.Root("client_key_pem")),
+		},
+		"missing-file": {
+			config:    DevOpsProviderModel{ClientKeyFile: types.StringValue("/nonexistent/key.pem")},
+			expectNil: true,
+			errorPath: pathPointer(path.Root("client_key_file")),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			tlsConfig := tlsConfigFromConfig(testCase.config, &diags)
+
+			if (tlsConfig == nil) != testCase.expectNil {
+				t.Errorf("expected nil config: %t, got %v", testCase.expectNil, tlsConfig)
+			}
+
+			if testCase.errorPath == nil {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", diags)
+				}
+				return
+			}
+
+			for _, d := range diags.Errors() {
+				if withPath, ok := d.(diag.DiagnosticWithPath); ok && withPath.Path().Equal(*testCase.errorPath) {
+					return
+				}
+			}
+			t.Errorf("expected error at %s, got %v", testCase.errorPath, diags)
+		})
+	}
+}
+
+func pathPointer(p path.Path) *path.Path {
+	return &p
+}
@@ -0,0 +1,259 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName identifies the provider's spans to OpenTelemetry.
+const tracerName = "github.com/madisonewebb/DOB-tf-providers/internal/provider"
+
+// serviceName is the OpenTelemetry service name of the provider, unless
+// OTEL_SERVICE_NAME says otherwise.
+const serviceName = "terraform-provider-devops-bootcamp"
+
+// tracingShutdownTimeout bounds how long exporting the last spans may
+// delay the exit of the provider process. go-plugin kills the process two
+// seconds after Serve returns, so this stays well below that.
+const tracingShutdownTimeout = 500 * time.Millisecond
+
+// tracingFlushTimeout bounds how long a background export of the spans
+// recorded so far may take.
+const tracingFlushTimeout = time.Second
+
+// tracingFlushing is set while a background export is running.
+var tracingFlushing atomic.Bool
+
+// tracing is the OpenTelemetry setup of this provider process. The first
+// configuration that enables tracing installs it; later ones reuse it.
+var tracing struct {
+	mu       sync.Mutex
+	provider *sdktrace.TracerProvider
+	root     trace.Span
+	file     *os.File
+}
+
+// tracingSettings says where spans are exported to.
+type tracingSettings struct {
+	// endpoint is the OTLP/HTTP endpoint URL. When empty, the exporter
+	// reads it from the OTEL_EXPORTER_OTLP_* environment variables.
+	endpoint string
+	otlp     bool
+
+	// file receives spans as JSON lines instead of, or as well as, OTLP.
+	file string
+}
+
+// tracingFromConfig works out whether and where to export traces. The
+// tracing_endpoint and tracing_file attributes enable tracing; otherwise
+// the standard OTEL_* environment variables do. OTEL_SDK_DISABLED turns
+// tracing off entirely.
+func tracingFromConfig(config DevOpsProviderModel, diags *diag.Diagnostics) (tracingSettings, bool) {
+	var settings tracingSettings
+
+	if !config.TracingEndpoint.IsNull() && !config.TracingEndpoint.IsUnknown() {
+		endpoint := config.TracingEndpoint.ValueString()
+		parsed, err := url.Parse(endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			diags.AddAttributeError(
+				path.Root("tracing_endpoint"),
+				"Invalid Tracing Endpoint",
+				fmt.Sprintf("The tracing_endpoint value %q must be an http or https URL of an OTLP/HTTP traces endpoint, such as \"http://localhost:4318/v1/traces\".", endpoint),
+			)
+		}
+		settings.endpoint = endpoint
+		settings.otlp = true
+	}
+
+	if !config.TracingFile.IsNull() && !config.TracingFile.IsUnknown() {
+		if config.TracingFile.ValueString() == "" {
+			diags.AddAttributeError(
+				path.Root("tracing_file"),
+				"Invalid Tracing File",
+				"The tracing_file value must not be empty.",
+			)
+		}
+		settings.file = config.TracingFile.ValueString()
+	}
+
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return settings, false
+	}
+	if settings.otlp || settings.file != "" {
+		return settings, true
+	}
+
+	switch strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")) {
+	case "none":
+		return settings, false
+	case "otlp":
+		settings.otlp = true
+	default:
+		settings.otlp = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+	}
+
+	return settings, settings.otlp
+}
+
+// setupTracing installs the global tracer provider, which the API client
+// records its spans with too, and starts a span covering the provider
+// process. That span continues the trace given by the TRACEPARENT
+// environment variable, if any, so a CI job can tie a Terraform run to
+// its own trace.
+func setupTracing(ctx context.Context, version string, settings tracingSettings) error {
+	tracing.mu.Lock()
+	defer tracing.mu.Unlock()
+
+	if tracing.provider != nil {
+		return nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", version),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return err
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	if settings.otlp {
+		var exporterOpts []otlptracehttp.Option
+		if settings.endpoint != "" {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(settings.endpoint))
+		}
+		exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	if settings.file != "" {
+		file, err := os.OpenFile(settings.file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return err
+		}
+		tracing.file = file
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	tracing.provider = sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tracing.provider)
+
+	parent := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{
+		"traceparent": os.Getenv("TRACEPARENT"),
+		"tracestate":  os.Getenv("TRACESTATE"),
+	})
+	_, tracing.root = tracing.provider.Tracer(tracerName).Start(parent, serviceName)
+
+	return nil
+}
+
+// startSpan starts a span for a provider operation. Terraform does not
+// pass a trace context to providers, so operations without one become
+// children of the span of the provider process.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		tracing.mu.Lock()
+		root := tracing.root
+		tracing.mu.Unlock()
+
+		if root != nil {
+			ctx = trace.ContextWithSpanContext(ctx, root.SpanContext())
+		}
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, name)
+}
+
+// endSpan marks span as failed when diags has errors, ends it and starts
+// exporting the spans recorded so far.
+func endSpan(span trace.Span, diags diag.Diagnostics) {
+	if errs := diags.Errors(); len(errs) > 0 {
+		span.SetStatus(codes.Error, errs[0].Summary())
+	}
+	span.End()
+	flushTracing()
+}
+
+// flushTracing starts exporting the buffered spans in the background. The
+// provider process may be killed before shutdownTracing finishes, so every
+// operation flushes its own spans instead of leaving them all to the end,
+// but without waiting on a slow or unreachable collector. While a flush is
+// running, further ones are skipped; their spans go out with the next.
+func flushTracing() {
+	tracing.mu.Lock()
+	provider := tracing.provider
+	tracing.mu.Unlock()
+
+	if provider == nil || !tracingFlushing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer tracingFlushing.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+
+		// Export failures are reported to the OpenTelemetry error handler
+		// and must not fail any operation.
+		_ = provider.ForceFlush(ctx)
+	}()
+}
+
+// shutdownTracing ends the span of the provider process and exports the
+// spans that are still buffered.
+func shutdownTracing(ctx context.Context) error {
+	tracing.mu.Lock()
+	defer tracing.mu.Unlock()
+
+	if tracing.provider == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tracingShutdownTimeout)
+	defer cancel()
+
+	tracing.root.End()
+	err := tracing.provider.Shutdown(ctx)
+	if tracing.file != nil {
+		err = errors.Join(err, tracing.file.Close())
+	}
+
+	tracing.provider, tracing.root, tracing.file = nil, nil, nil
+	otel.SetTracerProvider(noop.NewTracerProvider())
+
+	return err
+}
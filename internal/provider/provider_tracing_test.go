@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+	"github.com/madisonewebb/DOB-tf-providers/internal/fakeapi"
+)
+
+func TestTracingFromConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config    DevOpsProviderModel
+		env       map[string]string
+		expected  tracingSettings
+		enabled   bool
+		errorPath *path.Path
+	}{
+		"unset": {},
+		"endpoint": {
+			config:   DevOpsProviderModel{TracingEndpoint: types.StringValue("http://localhost:4318/v1/traces")},
+			expected: tracingSettings{endpoint: "http://localhost:4318/v1/traces", otlp: true},
+			enabled:  true,
+		},
+		"file": {
+			config:   DevOpsProviderModel{TracingFile: types.StringValue("traces.json")},
+			expected: tracingSettings{file: "traces.json"},
+			enabled:  true,
+		},
+		"env-endpoint": {
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"},
+			expected: tracingSettings{otlp: true},
+			enabled:  true,
+		},
+		"env-exporter-none": {
+			env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_TRACES_EXPORTER": "none"},
+		},
+		"sdk-disabled": {
+			config:   DevOpsProviderModel{TracingFile: types.StringValue("traces.json")},
+			env:      map[string]string{"OTEL_SDK_DISABLED": "true"},
+			expected: tracingSettings{file: "traces.json"},
+		},
+		"invalid-endpoint": {
+			config:    DevOpsProviderModel{TracingEndpoint: types.StringValue("localhost:4318")},
+			errorPath: pathPointer(path.Root("tracing_endpoint")),
+		},
+		"empty-file": {
+			config:    DevOpsProviderModel{TracingFile: types.StringValue("")},
+			errorPath: pathPointer(path.Root("tracing_file")),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{"OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"} {
+				t.Setenv(key, testCase.env[key])
+			}
+
+			var diags diag.Diagnostics
+			settings, enabled := tracingFromConfig(testCase.config, &diags)
+
+			if testCase.errorPath != nil {
+				if !diags.HasError() {
+					t.Fatal("expected error")
+				}
+				if errPath := diags.Errors()[0].(diag.DiagnosticWithPath).Path(); !errPath.Equal(*testCase.errorPath) {
+					t.Errorf("expected error at %s, got %s", testCase.errorPath, errPath)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if settings != testCase.expected || enabled != testCase.enabled {
+				t.Errorf("expected %+v (enabled %t), got %+v (enabled %t)", testCase.expected, testCase.enabled, settings, enabled)
+			}
+		})
+	}
+}
+
+func TestTracingJoinsOneTrace(t *testing.T) {
+	t.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	file := filepath.Join(t.TempDir(), "traces.json")
+
+	if err := setupTracing(context.Background(), "test", tracingSettings{file: file}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() { _ = shutdownTracing(context.Background()) })
+
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	c, err := client.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, span := startSpan(context.Background(), "devops-bootcamp_engineer Read")
+	_, err = c.GetEngineers(ctx)
+	endSpan(span, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	names := map[string]bool{}
+	decoder := json.NewDecoder(bufio.NewReader(f))
+	for decoder.More() {
+		var span struct {
+			Name        string
+			SpanContext struct{ TraceID string }
+		}
+		if err := decoder.Decode(&span); err != nil {
+			t.Fatal(err)
+		}
+		if span.SpanContext.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("expected span %q to continue TRACEPARENT, got trace %s", span.Name, span.SpanContext.TraceID)
+		}
+		names[span.Name] = true
+	}
+
+	for _, name := range []string{serviceName, "devops-bootcamp_engineer Read", "GET /engineers"} {
+		if !names[name] {
+			t.Errorf("expected a %q span, got %v", name, names)
+		}
+	}
+}
+
+func TestEndSpanFlushes(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+
+	if err := setupTracing(context.Background(), "test", tracingSettings{file: file}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() { _ = shutdownTracing(context.Background()) })
+
+	_, span := startSpan(context.Background(), "devops-bootcamp_engineer Create")
+	endSpan(span, nil)
+
+	// The span must be exported before shutdown, which may never finish.
+	// The export runs in the background, so give it a moment.
+	var data []byte
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		var err error
+		if data, err = os.ReadFile(file); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), `"devops-bootcamp_engineer Create"`) {
+			return
+		}
+	}
+	t.Errorf("expected the span to be flushed, got %q", data)
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/madisonewebb/DOB-tf-providers/internal/cassette"
+	"github.com/madisonewebb/DOB-tf-providers/internal/client"
+	"github.com/madisonewebb/DOB-tf-providers/internal/fakeapi"
+)
+
+// configureProvider runs Configure with only the endpoint set and returns
+// the API client handed to data sources.
+func configureProvider(t *testing.T, p *DevOpsProvider, endpoint string) *client.Client {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, key := range []string{"DEVOPS_ENDPOINT", "DEVOPS_TOKEN", "DEVOPS_USERNAME", "DEVOPS_PASSWORD"} {
+		t.Setenv(key, "")
+	}
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	values := map[string]tftypes.Value{}
+	for name, attribute := range schemaResp.Schema.Attributes {
+		values[name] = tftypes.NewValue(attribute.GetType().TerraformType(ctx), nil)
+	}
+	values["endpoint"] = tftypes.NewValue(tftypes.String, endpoint)
+
+	req := provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), values),
+		},
+	}
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	return resp.DataSourceData.(*client.Client)
+}
+
+func TestConfigureReplaysCassette(t *testing.T) {
+	server := fakeapi.NewServer()
+	server.AddEngineer(client.Engineer{Name: "Grace Hopper", Email: "grace.hopper@example.com"})
+	endpoint := server.URL
+
+	path := filepath.Join(t.TempDir(), "engineers_data_source.json")
+
+	recorder, err := cassette.New(path, cassette.ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, err := configureProvider(t, &DevOpsProvider{version: "test", transport: recorder}, endpoint).GetEngineers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	// The health check and the read can now only be answered by the
+	// cassette, so this fails unless Configure hands the transport on.
+	recorder, err = cassette.New(path, cassette.ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := configureProvider(t, &DevOpsProvider{version: "test", transport: recorder}, endpoint).GetEngineers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(replayed) != 1 || replayed[0] != live[0] {
+		t.Errorf("expected replay of %+v, got %+v", live, replayed)
+	}
+}
@@ -37,6 +37,11 @@ func main() {
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
 
+	// Serve returns once Terraform is done with the provider.
+	if shutdownErr := provider.Shutdown(context.Background()); shutdownErr != nil {
+		log.Print(shutdownErr.Error())
+	}
+
 	if err != nil {
 		log.Fatal(err.Error())
 	}